@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// AdaptiveTimeout Adapts the per-attempt timeout to the observed latency of successful attempts, so timeouts stay
+// tight without manual retuning as the latency of the dependency shifts. Latencies are smoothed inline as in the TCP
+// retransmission timeout (RFC 6298), without keeping samples:
+//
+//	timeout = factor * (smoothed latency + 4 * smoothed deviation), clamped to [min, max]
+//
+// Until the first observation, the timeout is max. Safe for concurrent use, may be shared by several Retry.
+type AdaptiveTimeout struct {
+	mu       sync.Mutex
+	factor   float64
+	min      time.Duration
+	max      time.Duration
+	smoothed float64
+	variance float64
+	observed bool
+}
+
+// NewAdaptiveTimeout initialize new AdaptiveTimeout
+// factor - multiplier of the estimated latency bound, values above 1 leave room for outliers
+// minTimeout - lower bound of the timeout
+// maxTimeout - upper bound of the timeout, used until the first observation
+func NewAdaptiveTimeout(factor float64, minTimeout time.Duration, maxTimeout time.Duration) *AdaptiveTimeout {
+	return &AdaptiveTimeout{factor: factor, min: minTimeout, max: maxTimeout}
+}
+
+// Observe records the latency of a successful attempt
+func (a *AdaptiveTimeout) Observe(latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sample := float64(latency)
+	if !a.observed {
+		a.observed = true
+		a.smoothed = sample
+		a.variance = sample / 2
+		return
+	}
+	deviation := a.smoothed - sample
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	a.variance = 0.75*a.variance + 0.25*deviation
+	a.smoothed = 0.875*a.smoothed + 0.125*sample
+}
+
+// Timeout returns the current timeout
+func (a *AdaptiveTimeout) Timeout() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.observed {
+		return a.max
+	}
+	timeout := time.Duration(a.factor * (a.smoothed + 4*a.variance))
+	if timeout < a.min {
+		return a.min
+	}
+	if a.max > 0 && timeout > a.max {
+		return a.max
+	}
+	return timeout
+}
+
+// WithAdaptiveAttemptTimeout adapts the per-attempt timeout to the latency of successful attempts. The timeout set
+// with WithAttemptTimeout, when positive, remains an upper bound. ExecuteHedged uses the timeout but does not feed
+// the latency of its attempts.
+func WithAdaptiveAttemptTimeout(timeout *AdaptiveTimeout) Option {
+	return func(r *Retry) {
+		r.adaptive = timeout
+	}
+}
+
+// attemptTimeout returns the timeout of the next attempt, negative when disabled
+func (r *Retry) attemptTimeout() time.Duration {
+	timeout := r.timeout
+	if r.adaptive != nil {
+		if adaptive := r.adaptive.Timeout(); adaptive > 0 && (timeout <= 0 || adaptive < timeout) {
+			timeout = adaptive
+		}
+	}
+	if timeout <= 0 {
+		return -1
+	}
+	return timeout
+}
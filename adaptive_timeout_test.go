@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_AdaptiveTimeout(t *testing.T) {
+
+	timeout := NewAdaptiveTimeout(2, time.Millisecond, time.Second)
+	if d := timeout.Timeout(); d != time.Second {
+		t.Fatalf("Timeout not equal, want: %v, got %v", time.Second, d)
+	}
+
+	// first sample: 2 * (10ms + 4 * 5ms)
+	timeout.Observe(10 * time.Millisecond)
+	if d := timeout.Timeout(); d != 60*time.Millisecond {
+		t.Fatalf("Timeout not equal, want: %v, got %v", 60*time.Millisecond, d)
+	}
+
+	// stable latency shrinks the deviation
+	for i := 0; i < 100; i++ {
+		timeout.Observe(10 * time.Millisecond)
+	}
+	if d := timeout.Timeout(); d < 20*time.Millisecond || d > 21*time.Millisecond {
+		t.Fatalf("Timeout not expected, got %v", d)
+	}
+
+	// the bounds are enforced
+	timeout.Observe(time.Hour)
+	if d := timeout.Timeout(); d != time.Second {
+		t.Fatalf("Timeout not equal, want: %v, got %v", time.Second, d)
+	}
+	small := NewAdaptiveTimeout(1, 5*time.Millisecond, 0)
+	small.Observe(time.Microsecond)
+	if d := small.Timeout(); d != 5*time.Millisecond {
+		t.Fatalf("Timeout not equal, want: %v, got %v", 5*time.Millisecond, d)
+	}
+}
+
+func Test_AdaptiveAttemptTimeout(t *testing.T) {
+
+	timeout := NewAdaptiveTimeout(1, 10*time.Millisecond, time.Second)
+	retries := NewWithOptions(
+		WithRetries(1),
+		WithFixedBackOff(time.Millisecond),
+		WithAttemptTimeout(time.Minute),
+		WithAdaptiveAttemptTimeout(timeout),
+	)
+
+	// the first attempt is bounded by the max of the adaptive timeout, not by the attempt timeout
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Second {
+			t.Fatalf("Deadline not expected")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+
+	// fast successes bring the timeout down to the min
+	err = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if d := timeout.Timeout(); d != 10*time.Millisecond {
+		t.Fatalf("Timeout not equal, want: %v, got %v", 10*time.Millisecond, d)
+	}
+}
@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AlertFunc is invoked when an operation enters the sustained-failure state, with the error of the give-up that
+// crossed the threshold and the number of give-ups within the window
+type AlertFunc func(ctx context.Context, err error, giveUps int)
+
+// GiveUpAlert Turns the outcomes of executions into an alerting signal. The operation enters the sustained-failure
+// state when the number of give-ups within a sliding window reaches the threshold, and leaves it on the next success.
+// The alert fires once per entry into the state, not on every give-up. Use one GiveUpAlert per operation, wiring its
+// methods as hooks:
+//
+//	alert := retry.NewGiveUpAlert(5, 10*time.Minute, page)
+//	retries := retry.NewWithOptions(
+//	    retry.WithOnGiveUp(alert.OnGiveUp),
+//	    retry.WithOnSuccess(alert.OnSuccess),
+//	)
+type GiveUpAlert struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	giveUps   []time.Time
+	firing    bool
+	alert     AlertFunc
+	resolve   func(ctx context.Context)
+	now       func() time.Time
+}
+
+// NewGiveUpAlert initialize new GiveUpAlert
+// threshold - number of give-ups that puts the operation in the sustained-failure state
+// window - period over which give-ups are counted
+func NewGiveUpAlert(threshold int, window time.Duration, alert AlertFunc) *GiveUpAlert {
+	return &GiveUpAlert{
+		threshold: threshold,
+		window:    window,
+		alert:     alert,
+		now:       time.Now,
+	}
+}
+
+// SetOnResolve Set the function invoked when the operation leaves the sustained-failure state
+func (a *GiveUpAlert) SetOnResolve(resolve func(ctx context.Context)) {
+	a.resolve = resolve
+}
+
+// OnGiveUp records a give-up, see OnGiveUp
+func (a *GiveUpAlert) OnGiveUp(ctx context.Context, err error, attempts int, elapsed time.Duration) {
+	a.mu.Lock()
+	now := a.now()
+	a.prune(now)
+	a.giveUps = append(a.giveUps, now)
+	count := len(a.giveUps)
+	fire := !a.firing && count >= a.threshold
+	if fire {
+		a.firing = true
+	}
+	a.mu.Unlock()
+
+	if fire && a.alert != nil {
+		a.alert(ctx, err, count)
+	}
+}
+
+// OnSuccess records a success, see OnSuccess
+func (a *GiveUpAlert) OnSuccess(ctx context.Context, attempts int, elapsed time.Duration) {
+	a.mu.Lock()
+	resolved := a.firing
+	a.firing = false
+	a.giveUps = a.giveUps[:0]
+	a.mu.Unlock()
+
+	if resolved && a.resolve != nil {
+		a.resolve(ctx)
+	}
+}
+
+// Firing reports whether the operation is in the sustained-failure state
+func (a *GiveUpAlert) Firing() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.firing
+}
+
+// prune drops the give-ups older than the window
+func (a *GiveUpAlert) prune(now time.Time) {
+	i := 0
+	for i < len(a.giveUps) && now.Sub(a.giveUps[i]) >= a.window {
+		i++
+	}
+	a.giveUps = append(a.giveUps[:0], a.giveUps[i:]...)
+}
@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_GiveUpAlert(t *testing.T) {
+
+	now := time.Now()
+	alerts, resolves := 0, 0
+	alert := NewGiveUpAlert(3, time.Minute, func(ctx context.Context, err error, giveUps int) {
+		alerts++
+		if giveUps != 3 {
+			t.Fatalf("GiveUps not equal, want: %d, got %d", 3, giveUps)
+		}
+	})
+	alert.SetOnResolve(func(ctx context.Context) {
+		resolves++
+	})
+	alert.now = func() time.Time { return now }
+
+	retries := NewWithOptions(
+		WithFixedBackOff(time.Millisecond),
+		WithOnGiveUp(alert.OnGiveUp),
+		WithOnSuccess(alert.OnSuccess),
+	)
+	fail := func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	}
+	succeed := func(ctx context.Context, attempt int) error {
+		return nil
+	}
+
+	// give-ups spread beyond the window don't fire
+	for i := 0; i < 4; i++ {
+		_ = retries.Execute(context.Background(), fail)
+		now = now.Add(40 * time.Second)
+	}
+	if alerts != 0 || alert.Firing() {
+		t.Fatalf("Alerts not equal, want: %d, got %d", 0, alerts)
+	}
+
+	for i := 0; i < 5; i++ {
+		_ = retries.Execute(context.Background(), fail)
+	}
+	if alerts != 1 || !alert.Firing() {
+		t.Fatalf("Alerts not equal, want: %d, got %d", 1, alerts)
+	}
+
+	_ = retries.Execute(context.Background(), succeed)
+	if resolves != 1 || alert.Firing() {
+		t.Fatalf("Resolves not equal, want: %d, got %d", 1, resolves)
+	}
+
+	// the counting restarts after a resolve
+	for i := 0; i < 3; i++ {
+		_ = retries.Execute(context.Background(), fail)
+	}
+	if alerts != 2 {
+		t.Fatalf("Alerts not equal, want: %d, got %d", 2, alerts)
+	}
+}
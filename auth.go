@@ -0,0 +1,40 @@
+package retry
+
+import "context"
+
+// authRefreshKey identifies the refresh of an AuthRefresh wrapper in the Execute
+type authRefreshKey struct{ _ byte }
+
+// AuthRefresh wraps a callback so that, when it fails with an error reported by isUnauthorized (e.g. HTTP 401 or gRPC
+// UNAUTHENTICATED), the credentials are refreshed and the call is immediately retried.
+//
+// The refresh runs at most once per Execute, even with concurrent (hedged) attempts, avoiding the common bug of
+// refreshing in a loop; the wrapper can be reused across executions. Outside an Execute, each call may refresh. The
+// refresh itself is retried with refreshRetry, when not nil.
+func AuthRefresh(
+	isUnauthorized func(err error) bool,
+	refresh func(ctx context.Context) error,
+	refreshRetry *Retry,
+	callback func(ctx context.Context, attempt int) error,
+) func(ctx context.Context, attempt int) error {
+	key := new(authRefreshKey)
+	return func(ctx context.Context, attempt int) error {
+		err := callback(ctx, attempt)
+		if err == nil || !isUnauthorized(err) || !claimOnce(ctx, key) {
+			return err
+		}
+
+		if refreshRetry != nil {
+			err = refreshRetry.Execute(ctx, func(ctx context.Context, attempt int) error {
+				return refresh(ctx)
+			})
+		} else {
+			err = refresh(ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		return callback(ctx, attempt)
+	}
+}
@@ -0,0 +1,125 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var unauthorizedErr = errors.New("unauthorized")
+
+func Test_AuthRefresh(t *testing.T) {
+
+	token := "expired"
+	countRefresh := 0
+	countCalls := 0
+
+	refreshRetry := New(2, nil)
+	refreshRetry.SetFixedBackOff(1)
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), AuthRefresh(
+		func(err error) bool {
+			return errors.Is(err, unauthorizedErr)
+		},
+		func(ctx context.Context) error {
+			countRefresh++
+			if countRefresh == 1 {
+				return customErr
+			}
+			token = "valid"
+			return nil
+		},
+		refreshRetry,
+		func(ctx context.Context, attempt int) error {
+			countCalls++
+			if token != "valid" {
+				return unauthorizedErr
+			}
+			return nil
+		},
+	))
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	if countRefresh != 2 {
+		t.Fatalf("Count refresh not equal, want: %d, got %d", 2, countRefresh)
+	}
+
+	if countCalls != 2 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 2, countCalls)
+	}
+}
+
+func Test_AuthRefreshOnce(t *testing.T) {
+
+	countRefresh := 0
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), AuthRefresh(
+		func(err error) bool {
+			return errors.Is(err, unauthorizedErr)
+		},
+		func(ctx context.Context) error {
+			countRefresh++
+			return nil
+		},
+		nil,
+		func(ctx context.Context, attempt int) error {
+			return unauthorizedErr
+		},
+	))
+
+	if !errors.Is(err, unauthorizedErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", unauthorizedErr, err)
+	}
+
+	if countRefresh != 1 {
+		t.Fatalf("Count refresh not equal, want: %d, got %d", 1, countRefresh)
+	}
+}
+
+func Test_AuthRefreshReusedWrapper(t *testing.T) {
+
+	countRefresh := 0
+	valid := false
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	callback := AuthRefresh(
+		func(err error) bool {
+			return errors.Is(err, unauthorizedErr)
+		},
+		func(ctx context.Context) error {
+			countRefresh++
+			valid = true
+			return nil
+		},
+		nil,
+		func(ctx context.Context, attempt int) error {
+			if !valid {
+				return unauthorizedErr
+			}
+			return nil
+		},
+	)
+
+	for i := 0; i < 2; i++ {
+		// the token expires between executions
+		valid = false
+		if err := retries.Execute(context.Background(), callback); err != nil {
+			t.Fatalf("Error not expected in execution %d: %v", i+1, err)
+		}
+	}
+
+	if countRefresh != 2 {
+		t.Fatalf("Count refresh not equal, want: %d, got %d", 2, countRefresh)
+	}
+}
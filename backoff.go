@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// BackoffStrategy computes the delay before the next attempt
+type BackoffStrategy interface {
+	// NextDelay returns the delay after the given (failed) attempt. err is the error that triggered the retry, allowing
+	// server-driven delays; it is nil when the delay is computed ahead of time (e.g. schedules and curves).
+	NextDelay(attempt int, err error) time.Duration
+}
+
+// LegacyBackoffStrategy the previous shape of BackoffStrategy, returning the delay in milliseconds
+type LegacyBackoffStrategy interface {
+	Next(attempt int) int
+}
+
+// BackoffFunc An adapter to allow the use of ordinary functions as BackoffStrategy
+type BackoffFunc func(attempt int, err error) time.Duration
+
+func (f BackoffFunc) NextDelay(attempt int, err error) time.Duration {
+	return f(attempt, err)
+}
+
+// FromLegacy adapts a LegacyBackoffStrategy, returning milliseconds, to BackoffStrategy
+func FromLegacy(strategy LegacyBackoffStrategy) BackoffStrategy {
+	return BackoffFunc(func(attempt int, err error) time.Duration {
+		return time.Duration(strategy.Next(attempt)) * time.Millisecond
+	})
+}
+
+// FixedBackOffStrategy A BackoffStrategy that pauses for a fixed period of time before continuing.
+type FixedBackOffStrategy struct {
+	period time.Duration
+}
+
+// NewFixedBackOff initialize new FixedBackOffStrategy
+func NewFixedBackOff(period time.Duration) *FixedBackOffStrategy {
+	return &FixedBackOffStrategy{period: period}
+}
+
+func (b *FixedBackOffStrategy) NextDelay(attempt int, err error) time.Duration {
+	return b.period
+}
+
+// ExponentialBackoffStrategy A BackoffStrategy that increases the back off period for each retry attempt in a given set
+// using the exponential function.
+type ExponentialBackoffStrategy struct {
+	initTime time.Duration
+	maxTime  time.Duration
+	factor   float64
+}
+
+// NewExponentialBackoff initialize new ExponentialBackoffStrategy
+func NewExponentialBackoff(initTime time.Duration, maxTime time.Duration, factor float64) *ExponentialBackoffStrategy {
+	return &ExponentialBackoffStrategy{initTime: initTime, maxTime: maxTime, factor: factor}
+}
+
+func (b *ExponentialBackoffStrategy) NextDelay(attempt int, err error) time.Duration {
+	return time.Duration(math.Min(math.Pow(b.factor, float64(attempt-1))*float64(b.initTime), float64(b.maxTime)))
+}
+
+// LinearBackoffStrategy A BackoffStrategy that increases the back off period by a fixed increment for each retry
+// attempt.
+type LinearBackoffStrategy struct {
+	initTime  time.Duration
+	increment time.Duration
+	maxTime   time.Duration
+}
+
+// NewLinearBackoff initialize new LinearBackoffStrategy
+func NewLinearBackoff(initTime time.Duration, increment time.Duration, maxTime time.Duration) *LinearBackoffStrategy {
+	return &LinearBackoffStrategy{initTime: initTime, increment: increment, maxTime: maxTime}
+}
+
+func (b *LinearBackoffStrategy) NextDelay(attempt int, err error) time.Duration {
+	delay := b.initTime + time.Duration(attempt-1)*b.increment
+	if delay > b.maxTime {
+		return b.maxTime
+	}
+	return delay
+}
+
+// FibonacciBackoffStrategy A BackoffStrategy that increases the back off period following the Fibonacci sequence
+// (1, 1, 2, 3, 5, 8...) multiplied by the initial time.
+type FibonacciBackoffStrategy struct {
+	initTime time.Duration
+	maxTime  time.Duration
+}
+
+// NewFibonacciBackoff initialize new FibonacciBackoffStrategy
+func NewFibonacciBackoff(initTime time.Duration, maxTime time.Duration) *FibonacciBackoffStrategy {
+	return &FibonacciBackoffStrategy{initTime: initTime, maxTime: maxTime}
+}
+
+func (b *FibonacciBackoffStrategy) NextDelay(attempt int, err error) time.Duration {
+	prev, curr := time.Duration(0), b.initTime
+	for i := 1; i < attempt; i++ {
+		prev, curr = curr, prev+curr
+		if curr >= b.maxTime {
+			return b.maxTime
+		}
+	}
+	if curr > b.maxTime {
+		return b.maxTime
+	}
+	return curr
+}
+
+// HashedJitterBackoffStrategy A BackoffStrategy that adds a stable offset, derived from a consistent hash of a key, to
+// the delay of another strategy. Retries of periodic jobs across a fleet land in stable, spread-out slots rather than
+// randomizing every run.
+type HashedJitterBackoffStrategy struct {
+	strategy BackoffStrategy
+	offset   time.Duration
+}
+
+// NewHashedJitterBackoffStrategy wraps the strategy with an offset in the range [0, spread) derived from key
+func NewHashedJitterBackoffStrategy(strategy BackoffStrategy, key string, spread time.Duration) *HashedJitterBackoffStrategy {
+	offset := time.Duration(0)
+	if spread > 0 {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		offset = time.Duration(h.Sum64() % uint64(spread))
+	}
+	return &HashedJitterBackoffStrategy{strategy: strategy, offset: offset}
+}
+
+func (b *HashedJitterBackoffStrategy) NextDelay(attempt int, err error) time.Duration {
+	return b.strategy.NextDelay(attempt, err) + b.offset
+}
+
+func (b *HashedJitterBackoffStrategy) forExecution() BackoffStrategy {
+	if _, ok := b.strategy.(executionBackoff); !ok {
+		return b
+	}
+	return &HashedJitterBackoffStrategy{strategy: executionBackoffOf(b.strategy), offset: b.offset}
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func assertDelays(t *testing.T, strategy BackoffStrategy, want ...time.Duration) {
+	t.Helper()
+	for i, d := range want {
+		if got := strategy.NextDelay(i+1, nil); got != d {
+			t.Fatalf("Delay of attempt %d not equal, want: %s, got %s", i+1, d, got)
+		}
+	}
+}
+
+func Test_LinearBackoff(t *testing.T) {
+	ms := time.Millisecond
+	assertDelays(t, NewLinearBackoff(500*ms, 250*ms, 1200*ms), 500*ms, 750*ms, 1000*ms, 1200*ms, 1200*ms)
+}
+
+func Test_FibonacciBackoff(t *testing.T) {
+	ms := time.Millisecond
+	assertDelays(t, NewFibonacciBackoff(100*ms, 700*ms), 100*ms, 100*ms, 200*ms, 300*ms, 500*ms, 700*ms, 700*ms)
+}
+
+type legacyBackoff struct{}
+
+func (b *legacyBackoff) Next(attempt int) int {
+	return 200 * attempt
+}
+
+func Test_FromLegacy(t *testing.T) {
+	ms := time.Millisecond
+	assertDelays(t, FromLegacy(&legacyBackoff{}), 200*ms, 400*ms, 600*ms)
+}
+
+type retryInErr struct {
+	delay time.Duration
+}
+
+func (e *retryInErr) Error() string {
+	return "retry in " + e.delay.String()
+}
+
+func Test_BackoffReceivesError(t *testing.T) {
+
+	var waits []time.Duration
+
+	retries := New(3, nil)
+	retries.Backoff = BackoffFunc(func(attempt int, err error) time.Duration {
+		var hint *retryInErr
+		if errors.As(err, &hint) {
+			return hint.delay
+		}
+		return time.Second
+	})
+	retries.SetWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}))
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 2 {
+			return &retryInErr{delay: 42 * time.Millisecond}
+		}
+		return executeFn(ctx, attempt)
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if len(waits) != 3 || waits[0] != time.Second || waits[1] != 42*time.Millisecond {
+		t.Fatalf("Waits not expected, got %v", waits)
+	}
+}
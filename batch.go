@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrBufferFull      = errors.New("retry: buffer is full")
+	ErrNotAcknowledged = errors.New("retry: records not acknowledged")
+)
+
+// FlushFunc sends a batch of records, returning the indexes (in records) of those acknowledged. On partial success it
+// must return a non-nil error along with the acknowledged indexes.
+type FlushFunc[T any] func(ctx context.Context, records []T) (acked []int, err error)
+
+// Flush sends the records with retries, only re-sending unacknowledged records on each attempt. Returns the records
+// still unacknowledged when giving up.
+func Flush[T any](ctx context.Context, r *Retry, records []T, send FlushFunc[T]) ([]T, error) {
+	pending := records
+	err := r.Execute(ctx, func(ctx context.Context, attempt int) error {
+		if len(pending) == 0 {
+			return nil
+		}
+		acked, err := send(ctx, pending)
+		pending = removeAcked(pending, acked)
+		if err == nil && len(pending) > 0 {
+			// every record was sent, only the acknowledged ones are known to be applied
+			return ErrNotAcknowledged
+		}
+		return err
+	})
+	return pending, err
+}
+
+func removeAcked[T any](records []T, acked []int) []T {
+	if len(acked) == 0 {
+		return records
+	}
+	ack := make(map[int]struct{}, len(acked))
+	for _, i := range acked {
+		ack[i] = struct{}{}
+	}
+	remaining := make([]T, 0, len(records))
+	for i, record := range records {
+		if _, ok := ack[i]; !ok {
+			remaining = append(remaining, record)
+		}
+	}
+	return remaining
+}
+
+// Buffer A bounded buffer of records for buffered writers (metrics, logs, events), flushed with Flush. Records not
+// acknowledged when a flush gives up are kept for the next flush.
+type Buffer[T any] struct {
+	mu      sync.Mutex
+	records []T
+	maxSize int
+	retry   *Retry
+	send    FlushFunc[T]
+}
+
+// NewBuffer initialize new Buffer holding at most maxSize records
+func NewBuffer[T any](maxSize int, r *Retry, send FlushFunc[T]) *Buffer[T] {
+	return &Buffer[T]{maxSize: maxSize, retry: r, send: send}
+}
+
+// Add appends a record to the buffer. Returns ErrBufferFull when the buffer holds maxSize records.
+func (b *Buffer[T]) Add(record T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.records) >= b.maxSize {
+		return ErrBufferFull
+	}
+	b.records = append(b.records, record)
+	return nil
+}
+
+// Len returns the number of records in the buffer
+func (b *Buffer[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.records)
+}
+
+// Flush sends the buffered records. Unacknowledged records are put back into the buffer on failure.
+func (b *Buffer[T]) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	records := b.records
+	b.records = nil
+	b.mu.Unlock()
+
+	pending, err := Flush(ctx, b.retry, records, b.send)
+
+	if len(pending) > 0 {
+		b.mu.Lock()
+		// records added during the flush go after the pending ones, dropping the newest beyond maxSize
+		b.records = append(pending, b.records...)
+		if len(b.records) > b.maxSize {
+			b.records = b.records[:b.maxSize]
+		}
+		b.mu.Unlock()
+	}
+	return err
+}
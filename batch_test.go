@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_Flush(t *testing.T) {
+
+	var sent [][]string
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	pending, err := Flush(context.Background(), retries, []string{"a", "b", "c", "d"}, func(ctx context.Context, records []string) ([]int, error) {
+		sent = append(sent, records)
+		switch len(sent) {
+		case 1:
+			return []int{0, 2}, customErr
+		case 2:
+			return []int{1}, customErr
+		}
+		return []int{0}, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if len(pending) != 0 {
+		t.Fatalf("Pending not expected, got %v", pending)
+	}
+
+	if len(sent) != 3 || len(sent[1]) != 2 || sent[1][0] != "b" || sent[1][1] != "d" || sent[2][0] != "b" {
+		t.Fatalf("Sent not equal, got %v", sent)
+	}
+}
+
+func Test_Buffer(t *testing.T) {
+
+	retries := New(1, nil)
+	retries.SetFixedBackOff(1)
+
+	buffer := NewBuffer(3, retries, func(ctx context.Context, records []int) ([]int, error) {
+		return []int{0}, customErr
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := buffer.Add(i); err != nil {
+			t.Fatalf("Error not expected")
+		}
+	}
+
+	if err := buffer.Add(3); err != ErrBufferFull {
+		t.Fatalf("Error not equal, want: %v, got %v", ErrBufferFull, err)
+	}
+
+	if err := buffer.Flush(context.Background()); !errors.Is(err, customErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", customErr, err)
+	}
+
+	// two attempts, one record acknowledged on each
+	if buffer.Len() != 1 {
+		t.Fatalf("Buffer length not equal, want: %d, got %d", 1, buffer.Len())
+	}
+}
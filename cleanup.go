@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+type cleanupKey struct{}
+
+type cleanups struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// OnAttemptCleanup registers a function to be run when the current attempt ends, before the next attempt starts or
+// Execute returns. Functions run in reverse order of registration, like defer. Outside an Execute the function is
+// not registered and false is returned.
+func OnAttemptCleanup(ctx context.Context, fn func()) bool {
+	c, ok := ctx.Value(cleanupKey{}).(*cleanups)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	c.fns = append(c.fns, fn)
+	c.mu.Unlock()
+	return true
+}
+
+func withCleanups(ctx context.Context) (context.Context, *cleanups) {
+	c := &cleanups{}
+	return context.WithValue(ctx, cleanupKey{}, c), c
+}
+
+func (c *cleanups) run() {
+	c.mu.Lock()
+	fns := c.fns
+	c.fns = nil
+	c.mu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
+
+type holdKey struct{}
+
+// hold delays the cancellation of an attempt context while results bound to it are still in use
+type hold struct {
+	mu      sync.Mutex
+	holders int
+	ended   bool
+	cancels []func()
+}
+
+// HoldAttempt delays the cancellation of the attempt context, done by Execute when the attempt returns (attempt
+// timeout, watchdog), until the returned release function is called. Allows a result bound to the context, such as an
+// HTTP response body, to be consumed after the callback returns. Deadlines still apply. Outside an Execute, or after
+// the attempt has returned, release is a no-op.
+func HoldAttempt(ctx context.Context) (release func()) {
+	h, ok := ctx.Value(holdKey{}).(*hold)
+	if !ok {
+		return func() {}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.ended {
+		return func() {}
+	}
+	h.holders++
+	var once sync.Once
+	return func() {
+		once.Do(h.release)
+	}
+}
+
+func withHold(ctx context.Context) (context.Context, *hold) {
+	h := &hold{}
+	return context.WithValue(ctx, holdKey{}, h), h
+}
+
+// cancelAfterHolds calls cancel once the attempt of the context is released, or immediately if it is not held
+func cancelAfterHolds(ctx context.Context, cancel func()) {
+	if h, ok := ctx.Value(holdKey{}).(*hold); ok {
+		h.mu.Lock()
+		if h.holders > 0 {
+			h.cancels = append(h.cancels, cancel)
+			h.mu.Unlock()
+			return
+		}
+		h.mu.Unlock()
+	}
+	cancel()
+}
+
+// end marks the end of the attempt, no new holder is accepted
+func (h *hold) end() {
+	h.mu.Lock()
+	h.ended = true
+	h.mu.Unlock()
+}
+
+func (h *hold) release() {
+	h.mu.Lock()
+	h.holders--
+	var cancels []func()
+	if h.holders == 0 {
+		cancels = h.cancels
+		h.cancels = nil
+	}
+	h.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
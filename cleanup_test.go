@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_OnAttemptCleanup(t *testing.T) {
+
+	var events []string
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		events = append(events, "open")
+		OnAttemptCleanup(ctx, func() { events = append(events, "close-conn") })
+		OnAttemptCleanup(ctx, func() { events = append(events, "close-file") })
+		if attempt < 2 {
+			return customErr
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	want := []string{"open", "close-file", "close-conn", "open", "close-file", "close-conn"}
+	if len(events) != len(want) {
+		t.Fatalf("Events not equal, want: %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("Events not equal, want: %v, got %v", want, events)
+		}
+	}
+
+	if OnAttemptCleanup(context.Background(), func() {}) {
+		t.Fatalf("Cleanup outside Execute should not be registered")
+	}
+}
+
+func Test_HoldAttempt(t *testing.T) {
+
+	retries := NewWithOptions(WithRetries(0), WithAttemptTimeout(time.Minute))
+
+	var held context.Context
+	var release func()
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		held = ctx
+		release = HoldAttempt(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	if held.Err() != nil {
+		t.Fatalf("Held attempt context canceled before release: %v", held.Err())
+	}
+	release()
+	if held.Err() == nil {
+		t.Fatalf("Attempt context not canceled after release")
+	}
+}
@@ -0,0 +1,32 @@
+package retry
+
+import "time"
+
+// Clock abstracts the time source of an execution: the backoff waits and the measure of the elapsed time (max elapsed
+// time, stale inputs, Error.Elapsed and hook durations). Tests can use the fake clock of the retrytest package to run
+// long schedules without sleeping.
+//
+// Attempt timeouts and the watchdog still use runtime timers.
+type Clock interface {
+	Waiter
+	// Now returns the current time
+	Now() time.Time
+}
+
+// WithClock the time source of executions. A Waiter set with WithWaiter takes precedence for backoff waits.
+func WithClock(clock Clock) Option {
+	return func(r *Retry) {
+		r.clock = clock
+	}
+}
+
+func (r *Retry) now() time.Time {
+	if r.clock != nil {
+		return r.clock.Now()
+	}
+	return time.Now()
+}
+
+func (r *Retry) since(t time.Time) time.Duration {
+	return r.now().Sub(t)
+}
@@ -0,0 +1,39 @@
+package retry
+
+import "context"
+
+type noRetryKey struct{}
+
+// NoRetryContext returns a context that suppresses retries in any Execute using it: the callback is invoked once and
+// its error returned, as if the number of retries were 0. Useful to fail fast deep in a stack without plumbing options
+// through every layer.
+func NoRetryContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// IsNoRetry reports whether retries are suppressed for the context. See NoRetryContext.
+func IsNoRetry(ctx context.Context) bool {
+	v, _ := ctx.Value(noRetryKey{}).(bool)
+	return v
+}
+
+// WithoutValues returns an AttemptContext that hides the values of the given keys from each attempt's context
+func WithoutValues(keys ...any) AttemptContext {
+	return func(ctx context.Context, attempt int) context.Context {
+		return &maskedContext{Context: ctx, keys: keys}
+	}
+}
+
+type maskedContext struct {
+	context.Context
+	keys []any
+}
+
+func (c *maskedContext) Value(key any) any {
+	for _, k := range c.keys {
+		if k == key {
+			return nil
+		}
+	}
+	return c.Context.Value(key)
+}
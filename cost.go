@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+type costKey struct{}
+
+// Cost The units of a resource (bytes sent, API units, tokens) consumed by the attempts of a Retry
+type Cost struct {
+	Total   float64 // units consumed by all attempts
+	Retries float64 // units consumed by attempts after the first, i.e. the cost of retrying
+}
+
+type costs struct {
+	mu        sync.Mutex
+	resources map[string]Cost
+}
+
+type attemptCost struct {
+	costs *costs
+	retry bool
+}
+
+// ReportCost reports the units of a resource consumed by the current attempt, aggregated per resource in the Costs of
+// the Retry, so the cost of retries can be quantified per dependency. Outside an Execute the cost is dropped and false
+// is returned.
+func ReportCost(ctx context.Context, resource string, units float64) bool {
+	a, ok := ctx.Value(costKey{}).(attemptCost)
+	if !ok {
+		return false
+	}
+	a.costs.add(resource, units, a.retry)
+	return true
+}
+
+// withCost attaches the cost accumulator of this Retry to the context of an attempt
+func (r *Retry) withCost(ctx context.Context, attempt int) context.Context {
+	if r.counters == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, costKey{}, attemptCost{costs: &r.counters.costs, retry: attempt > 1})
+}
+
+func (c *costs) add(resource string, units float64, retry bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resources == nil {
+		c.resources = map[string]Cost{}
+	}
+	cost := c.resources[resource]
+	cost.Total += units
+	if retry {
+		cost.Retries += units
+	}
+	c.resources[resource] = cost
+}
+
+func (c *costs) snapshot() map[string]Cost {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.resources) == 0 {
+		return nil
+	}
+	snapshot := make(map[string]Cost, len(c.resources))
+	for resource, cost := range c.resources {
+		snapshot[resource] = cost
+	}
+	return snapshot
+}
+
+// Costs returns a snapshot of the units reported with ReportCost by the attempts of this Retry, per resource. Safe for
+// concurrent use.
+func (r *Retry) Costs() map[string]Cost {
+	if r.counters == nil {
+		return nil
+	}
+	return r.counters.costs.snapshot()
+}
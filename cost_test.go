@@ -0,0 +1,41 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_ReportCost(t *testing.T) {
+
+	retries := NewWithOptions(WithRetries(2), WithFixedBackOff(time.Millisecond))
+
+	for i := 0; i < 2; i++ {
+		err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+			if !ReportCost(ctx, "bytes", 100) {
+				t.Fatalf("Cost not reported")
+			}
+			ReportCost(ctx, "units", 1)
+			if attempt < 3 {
+				return errors.New("fail")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Error not expected, got %v", err)
+		}
+	}
+
+	costs := retries.Costs()
+	if bytes := costs["bytes"]; bytes.Total != 600 || bytes.Retries != 400 {
+		t.Fatalf("Cost not expected, got %+v", bytes)
+	}
+	if units := costs["units"]; units.Total != 6 || units.Retries != 4 {
+		t.Fatalf("Cost not expected, got %+v", units)
+	}
+
+	if ReportCost(context.Background(), "bytes", 1) {
+		t.Fatalf("Cost reported outside an execution")
+	}
+}
@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CurvePoint the delay before a retry and the cumulative time spent waiting until it
+type CurvePoint struct {
+	Attempt    int
+	Delay      time.Duration
+	Cumulative time.Duration
+}
+
+// Curve returns the delay curve of a strategy for the given number of retries, for plotting or documentation. Empty
+// for a negative number of retries.
+func Curve(strategy BackoffStrategy, retries int) []CurvePoint {
+	if retries < 0 {
+		retries = 0
+	}
+	points := make([]CurvePoint, retries)
+	cumulative := time.Duration(0)
+	for i := range points {
+		delay := strategy.NextDelay(i+1, nil)
+		cumulative += delay
+		points[i] = CurvePoint{Attempt: i + 1, Delay: delay, Cumulative: cumulative}
+	}
+	return points
+}
+
+// Curve returns the delay curve of this Retry. With unlimited retries, the curve is computed for the given limit.
+func (r *Retry) Curve(limit int) []CurvePoint {
+	if !r.unlimited && r.retries < limit {
+		limit = r.retries
+	}
+	return Curve(r.backoffStrategy(), limit)
+}
+
+// RenderCurve renders the curve as an ASCII chart, one line per retry with a bar proportional to the delay, up to
+// width characters
+//
+//	retry 1  |####                                    | 500ms (total 500ms)
+//	retry 2  |########                                | 1s (total 1.5s)
+func RenderCurve(points []CurvePoint, width int) string {
+	if width < 0 {
+		width = 0
+	}
+	maxDelay := time.Duration(0)
+	for _, p := range points {
+		if p.Delay > maxDelay {
+			maxDelay = p.Delay
+		}
+	}
+
+	label := len(fmt.Sprintf("retry %d", len(points)))
+
+	var b strings.Builder
+	for _, p := range points {
+		bar := 0
+		if maxDelay > 0 {
+			bar = int(int64(width) * int64(p.Delay) / int64(maxDelay))
+		}
+		if bar < 0 {
+			bar = 0
+		}
+		fmt.Fprintf(&b, "%-*s |%s%s| %s (total %s)\n",
+			label, fmt.Sprintf("retry %d", p.Attempt),
+			strings.Repeat("#", bar), strings.Repeat(" ", width-bar),
+			p.Delay, p.Cumulative,
+		)
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Curve(t *testing.T) {
+
+	retries := New(4, nil)
+	retries.SetExponentialBackoff(500, 1500, 2)
+
+	points := retries.Curve(10)
+
+	if len(points) != 4 {
+		t.Fatalf("Points count not equal, want: %d, got %d", 4, len(points))
+	}
+
+	if points[3].Delay != 1500*time.Millisecond || points[3].Cumulative != 4500*time.Millisecond {
+		t.Fatalf("Point not equal, got %+v", points[3])
+	}
+
+	want := "" +
+		"retry 1 |###       | 500ms (total 500ms)\n" +
+		"retry 2 |######    | 1s (total 1.5s)\n" +
+		"retry 3 |##########| 1.5s (total 3s)\n" +
+		"retry 4 |##########| 1.5s (total 4.5s)\n"
+
+	if got := RenderCurve(points, 10); got != want {
+		t.Fatalf("Render not equal, want:\n%s\ngot:\n%s", want, got)
+	}
+
+	if points = New(-1, nil).Curve(3); len(points) != 3 {
+		t.Fatalf("Points count not equal, want: %d, got %d", 3, len(points))
+	}
+}
+
+func Test_RenderCurveSmallWidth(t *testing.T) {
+
+	points := Curve(NewFixedBackOff(time.Second), 2)
+
+	for _, width := range []int{-5, 0, 1} {
+		if got := RenderCurve(points, width); len(got) == 0 {
+			t.Fatalf("Render empty with width %d", width)
+		}
+	}
+
+	want := "retry 1 || 1s (total 1s)\nretry 2 || 1s (total 2s)\n"
+	if got := RenderCurve(points, 0); got != want {
+		t.Fatalf("Render not equal, want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func Test_CurveNegative(t *testing.T) {
+
+	if points := New(-1, nil).Curve(-1); len(points) != 0 {
+		t.Fatalf("Points not expected, got %v", points)
+	}
+
+	if points := Curve(NewFixedBackOff(time.Second), -3); len(points) != 0 {
+		t.Fatalf("Points not expected, got %v", points)
+	}
+}
@@ -0,0 +1,32 @@
+package retry
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrEmptyLadder = errors.New("retry: empty degradation ladder")
+
+// Degrade unifies retry and graceful degradation: each variant of the ladder, ordered from the preferred to the
+// cheapest (full query, cached summary, static default), runs as a retry phase with r, and the next variant is only
+// tried when the previous phase gives up. Returns the value of the first phase that succeeds with the index of its
+// variant. When every phase gives up, or the context is done, returns an error joining the error of each phase.
+// Returns ErrEmptyLadder without any variant.
+func Degrade[T any](ctx context.Context, r *Retry, ladder ...func(ctx context.Context, attempt int) (T, error)) (T, int, error) {
+	var result T
+	if len(ladder) == 0 {
+		return result, -1, ErrEmptyLadder
+	}
+	var errs []error
+	for i, fn := range ladder {
+		value, err := Do(ctx, r, fn)
+		if err == nil {
+			return value, i, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return result, -1, errors.Join(errs...)
+}
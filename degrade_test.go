@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Degrade(t *testing.T) {
+
+	retries := NewWithOptions(WithRetries(1), WithFixedBackOff(time.Millisecond))
+	errQuery := errors.New("query failed")
+
+	calls := 0
+	value, rung, err := Degrade(context.Background(), retries,
+		func(ctx context.Context, attempt int) (string, error) {
+			calls++
+			return "", errQuery
+		},
+		func(ctx context.Context, attempt int) (string, error) {
+			calls++
+			return "cached", nil
+		},
+		func(ctx context.Context, attempt int) (string, error) {
+			t.Fatalf("Cheaper variant not expected")
+			return "", nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if value != "cached" || rung != 1 {
+		t.Fatalf("Result not expected, got %q (%d)", value, rung)
+	}
+	if calls != 3 {
+		t.Fatalf("Calls not equal, want: %d, got %d", 3, calls)
+	}
+}
+
+func Test_DegradeGiveUp(t *testing.T) {
+
+	retries := NewWithOptions(WithFixedBackOff(time.Millisecond))
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	_, rung, err := Degrade(context.Background(), retries,
+		func(ctx context.Context, attempt int) (int, error) {
+			return 0, errFirst
+		},
+		func(ctx context.Context, attempt int) (int, error) {
+			return 0, errSecond
+		},
+	)
+	if rung != -1 || !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Fatalf("Error not expected, got %v (%d)", err, rung)
+	}
+
+	// a canceled context stops the descent
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, err = Degrade(ctx, retries,
+		func(ctx context.Context, attempt int) (int, error) {
+			cancel()
+			return 0, errFirst
+		},
+		func(ctx context.Context, attempt int) (int, error) {
+			t.Fatalf("Cheaper variant not expected")
+			return 0, nil
+		},
+	)
+	if !errors.Is(err, errFirst) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+}
+
+func Test_DegradeEmptyLadder(t *testing.T) {
+
+	if _, rung, err := Degrade[string](context.Background(), New(1, nil)); err != ErrEmptyLadder || rung != -1 {
+		t.Fatalf("Error not equal, want: %v, got %v (rung %d)", ErrEmptyLadder, err, rung)
+	}
+}
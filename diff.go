@@ -0,0 +1,142 @@
+package retry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change kinds
+const (
+	ChangeIncreased = "increased"
+	ChangeDecreased = "decreased"
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeModified  = "modified"
+)
+
+// Change A semantic difference between two Retry configurations
+type Change struct {
+	Field string
+	Kind  string
+	From  string
+	To    string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s: %s -> %s", c.Field, c.Kind, c.From, c.To)
+}
+
+// policyField a field of the configuration, with its numeric value when comparable
+type policyField struct {
+	name    string
+	value   string
+	numeric float64
+	set     bool
+}
+
+// Diff describes the semantic differences between two Retry configurations (more attempts, longer max delay, removed
+// jitter), so config reviews and hot-reload logs can show what actually changed.
+func Diff(a, b *Retry) []Change {
+	fa, fb := describePolicy(a), describePolicy(b)
+
+	var changes []Change
+	for i := range fa {
+		from, to := fa[i], fb[i]
+		if from.value == to.value {
+			continue
+		}
+		c := Change{Field: from.name, From: from.value, To: to.value, Kind: ChangeModified}
+		switch {
+		case !from.set:
+			c.Kind = ChangeAdded
+		case !to.set:
+			c.Kind = ChangeRemoved
+		case to.numeric > from.numeric:
+			c.Kind = ChangeIncreased
+		case to.numeric < from.numeric:
+			c.Kind = ChangeDecreased
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// describePolicy describes the settings compared by Diff and hashed by Freeze. Custom strategies are only described by
+// their type.
+func describePolicy(r *Retry) []policyField {
+	retries := policyField{name: "retries", value: strconv.Itoa(r.retries), numeric: float64(r.retries), set: true}
+	if r.unlimited {
+		retries.value = "unlimited"
+		retries.numeric = float64(int(^uint(0) >> 1))
+	}
+
+	// unwrap the decorators, describing each layer
+	strategy := r.backoffStrategy()
+	var jitters []string
+	offset := policyField{name: "hashed jitter offset", value: "none"}
+	tick := policyField{name: "tick", value: "none"}
+unwrap:
+	for {
+		switch s := strategy.(type) {
+		case *JitterBackoffStrategy:
+			strategy = s.strategy
+			if s.mode != NoJitter {
+				jitters = append(jitters, s.mode.String())
+			}
+		case *HashedJitterBackoffStrategy:
+			strategy = s.strategy
+			jitters = append(jitters, "hashed")
+			offset = durationField(offset.name, s.offset)
+		case *TickBackoffStrategy:
+			strategy = s.strategy
+			tick = durationField(tick.name, s.tick)
+		default:
+			break unwrap
+		}
+	}
+	jitter := policyField{name: "jitter", value: "none"}
+	if len(jitters) > 0 {
+		jitter = policyField{name: "jitter", value: strings.Join(jitters, "+"), set: true}
+	}
+
+	backoff := policyField{name: "backoff", value: fmt.Sprintf("%T", strategy), set: true}
+	initial := policyField{name: "initial delay", value: "none"}
+	maxDelay := policyField{name: "max delay", value: "none"}
+	factor := policyField{name: "factor", value: "none"}
+	increment := policyField{name: "increment", value: "none"}
+	switch s := strategy.(type) {
+	case *FixedBackOffStrategy:
+		backoff.value = "fixed"
+		initial = durationField("initial delay", s.period)
+		maxDelay = durationField("max delay", s.period)
+	case *ExponentialBackoffStrategy:
+		backoff.value = "exponential"
+		initial = durationField("initial delay", s.initTime)
+		maxDelay = durationField("max delay", s.maxTime)
+		factor = policyField{name: "factor", value: strconv.FormatFloat(s.factor, 'g', -1, 64), numeric: s.factor, set: true}
+	case *LinearBackoffStrategy:
+		backoff.value = "linear"
+		initial = durationField("initial delay", s.initTime)
+		maxDelay = durationField("max delay", s.maxTime)
+		increment = durationField("increment", s.increment)
+	case *FibonacciBackoffStrategy:
+		backoff.value = "fibonacci"
+		initial = durationField("initial delay", s.initTime)
+		maxDelay = durationField("max delay", s.maxTime)
+	}
+
+	fields := []policyField{retries, backoff, initial, maxDelay, factor, increment, jitter, offset, tick}
+	for _, f := range []policyField{durationField("attempt timeout", r.timeout), durationField("max elapsed time", r.maxElapsed)} {
+		if f.numeric <= 0 {
+			f = policyField{name: f.name, value: "none"}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func durationField(name string, d time.Duration) policyField {
+	return policyField{name: name, value: d.String(), numeric: float64(d), set: true}
+}
@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Diff(t *testing.T) {
+
+	a := New(3, nil)
+	a.SetExponentialBackoffWithJitter(500, 5000, 2, FullJitter)
+
+	b := New(5, nil)
+	b.SetExponentialBackoff(500, 10000, 2)
+	b.SetMaxElapsedTime(time.Minute)
+
+	changes := Diff(a, b)
+
+	want := []string{
+		"retries increased: 3 -> 5",
+		"max delay increased: 5s -> 10s",
+		"jitter removed: full -> none",
+		"max elapsed time added: none -> 1m0s",
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("Changes not equal, want: %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i].String() != want[i] {
+			t.Fatalf("Change not equal, want: %s, got %s", want[i], changes[i])
+		}
+	}
+
+	if changes = Diff(a, a); len(changes) != 0 {
+		t.Fatalf("Changes not expected, got %v", changes)
+	}
+
+	changes = Diff(New(3, nil), New(-1, nil))
+	if len(changes) != 1 || changes[0].String() != "retries increased: 3 -> unlimited" {
+		t.Fatalf("Changes not expected, got %v", changes)
+	}
+}
+
+func Test_DiffWrappedStrategies(t *testing.T) {
+
+	cases := []struct {
+		a, b *Retry
+		want []string
+	}{
+		{
+			NewWithOptions(WithLinearBackoff(time.Second, time.Second, time.Minute)),
+			NewWithOptions(WithLinearBackoff(time.Second, 10*time.Second, time.Minute)),
+			[]string{"increment increased: 1s -> 10s"},
+		},
+		{
+			NewWithOptions(WithExponentialBackoff(time.Second, time.Minute, 2), WithBackoffJitter(FullJitter, nil), WithHashedJitter("job", time.Second)),
+			NewWithOptions(WithExponentialBackoff(time.Second, time.Minute, 3), WithBackoffJitter(EqualJitter, nil), WithHashedJitter("job", time.Second)),
+			[]string{"factor increased: 2 -> 3", "jitter modified: hashed+full -> hashed+equal"},
+		},
+		{
+			NewWithOptions(WithBackoff(NewTickBackoff(NewFixedBackOff(time.Second), 100*time.Millisecond))),
+			NewWithOptions(WithBackoff(NewTickBackoff(NewFixedBackOff(time.Hour), time.Second))),
+			[]string{
+				"initial delay increased: 1s -> 1h0m0s",
+				"max delay increased: 1s -> 1h0m0s",
+				"tick increased: 100ms -> 1s",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		changes := Diff(c.a, c.b)
+		if len(changes) != len(c.want) {
+			t.Fatalf("Changes not equal, want: %v, got %v", c.want, changes)
+		}
+		for i := range c.want {
+			if changes[i].String() != c.want[i] {
+				t.Fatalf("Change not equal, want: %s, got %s", c.want[i], changes[i])
+			}
+		}
+	}
+}
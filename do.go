@@ -0,0 +1,14 @@
+package retry
+
+import "context"
+
+// Do same as Execute, for callbacks that return a value. Returns the value and error of the last attempt.
+func Do[T any](ctx context.Context, r *Retry, fn func(ctx context.Context, attempt int) (T, error)) (T, error) {
+	var result T
+	err := r.Execute(ctx, func(ctx context.Context, attempt int) error {
+		var err error
+		result, err = fn(ctx, attempt)
+		return err
+	})
+	return result, err
+}
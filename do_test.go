@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Do(t *testing.T) {
+
+	countError := 0
+
+	retries := New(3, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		countError++
+	})
+	retries.SetFixedBackOff(1)
+
+	body, err := Do(context.Background(), retries, func(ctx context.Context, attempt int) (string, error) {
+		if err := executeFn(ctx, attempt); err != nil {
+			return "", err
+		}
+		return "body", nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if body != "body" {
+		t.Fatalf("Result not equal, want: %s, got %s", "body", body)
+	}
+
+	if countError != 3 {
+		t.Fatalf("Count error not equal, want: %d, got %d", 3, countError)
+	}
+}
+
+func Test_DoError(t *testing.T) {
+
+	retries := New(1, nil)
+	retries.SetFixedBackOff(1)
+
+	status, err := Do(context.Background(), retries, func(ctx context.Context, attempt int) (int, error) {
+		return 500 + attempt, customErr
+	})
+
+	if !errors.Is(err, customErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", customErr, err)
+	}
+
+	// value of the last attempt
+	if status != 502 {
+		t.Fatalf("Result not equal, want: %d, got %d", 502, status)
+	}
+}
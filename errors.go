@@ -0,0 +1,176 @@
+package retry
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnrecoverable = errors.New("retry: unrecoverable error")
+
+type unrecoverableError struct {
+	err error
+}
+
+func (e *unrecoverableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unrecoverableError) Unwrap() error {
+	return e.err
+}
+
+func (e *unrecoverableError) Is(target error) bool {
+	return target == ErrUnrecoverable
+}
+
+// Unrecoverable wraps an error to abort the execution immediately, without further retries. Execute returns it as the
+// last attempt error of an *Error, so both the wrapped error and ErrUnrecoverable are detectable with errors.Is.
+func Unrecoverable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &unrecoverableError{err: err}
+}
+
+// Permanent is an alias of Unrecoverable
+func Permanent(err error) error {
+	return Unrecoverable(err)
+}
+
+// IsUnrecoverable reports whether the error was marked with Unrecoverable
+func IsUnrecoverable(err error) bool {
+	return errors.Is(err, ErrUnrecoverable)
+}
+
+// maxErrors maximum number of attempt errors kept by Error, the oldest are discarded
+const maxErrors = 100
+
+// Error returned by Execute when giving up, records the errors of the attempts. Supports errors.Is and errors.As
+// against any of the attempt errors and the abort reason (e.g. context.Canceled).
+type Error struct {
+	errors     []error
+	attempts   int
+	elapsed    time.Duration
+	warnings   []error
+	policyHash string
+	cause      error
+}
+
+func (e *Error) add(err error) {
+	e.attempts++
+	if len(e.errors) == maxErrors {
+		e.errors = append(e.errors[:0], e.errors[1:]...)
+	}
+	e.errors = append(e.errors, err)
+}
+
+// abort finishes the history with the given abort reason. Without any attempt error, the reason itself is returned.
+func (e *Error) abort(cause error, elapsed time.Duration) error {
+	if e.attempts == 0 {
+		return cause
+	}
+	e.cause = cause
+	e.elapsed = elapsed
+	return e
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("retry: ")
+	b.WriteString(strconv.Itoa(e.attempts))
+	if e.attempts == 1 {
+		b.WriteString(" attempt in ")
+	} else {
+		b.WriteString(" attempts in ")
+	}
+	b.WriteString(e.elapsed.String())
+	if e.cause != nil {
+		b.WriteString(", aborted: ")
+		b.WriteString(e.cause.Error())
+	}
+	if last := e.LastError(); last != nil {
+		b.WriteString(": ")
+		b.WriteString(last.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the attempt errors followed by the abort reason, if any
+func (e *Error) Unwrap() []error {
+	if e.cause == nil {
+		return e.errors
+	}
+	return append(append([]error{}, e.errors...), e.cause)
+}
+
+// Attempts returns the error of each attempt, in order. Only the last 100 errors are kept.
+func (e *Error) Attempts() []error {
+	return e.errors
+}
+
+// AttemptCount returns the number of attempts made
+func (e *Error) AttemptCount() int {
+	return e.attempts
+}
+
+// LastError returns the error of the last attempt, nil without any attempt error
+func (e *Error) LastError() error {
+	if len(e.errors) == 0 {
+		return nil
+	}
+	return e.errors[len(e.errors)-1]
+}
+
+// Cause returns the reason the execution was aborted (e.g. context.Canceled, ErrStale), nil when the retries were
+// exhausted or the error was not retryable
+func (e *Error) Cause() error {
+	return e.cause
+}
+
+// Elapsed returns the total time of the execution, including backoff waits
+func (e *Error) Elapsed() time.Duration {
+	return e.elapsed
+}
+
+// Warnings returns the non-fatal warnings reported with Warn during the execution
+func (e *Error) Warnings() []error {
+	return e.warnings
+}
+
+// PolicyHash returns the hash of the policy of the Retry that gave up, empty if it was not frozen
+func (e *Error) PolicyHash() string {
+	return e.policyHash
+}
+
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter wraps an error with the delay requested by the server (e.g. a Retry-After header) before the next
+// attempt, overriding the delay computed by the BackoffStrategy.
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, delay: delay}
+}
+
+// RetryAfterDelay returns the delay of an error wrapped with RetryAfter
+func RetryAfterDelay(err error) (time.Duration, bool) {
+	var e *retryAfterError
+	if errors.As(err, &e) {
+		return e.delay, true
+	}
+	return 0, false
+}
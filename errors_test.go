@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type statusErr struct {
+	code int
+}
+
+func (e *statusErr) Error() string {
+	return "status"
+}
+
+func Test_Error(t *testing.T) {
+
+	firstErr := &statusErr{code: 503}
+
+	retries := New(2, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			return firstErr
+		}
+		return customErr
+	})
+
+	var retryErr *Error
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("*Error expected, got %T", err)
+	}
+
+	if retryErr.AttemptCount() != 3 || len(retryErr.Attempts()) != 3 {
+		t.Fatalf("Attempts not equal, want: %d, got %d", 3, retryErr.AttemptCount())
+	}
+
+	if retryErr.LastError() != customErr || retryErr.Cause() != nil {
+		t.Fatalf("LastError not equal, want: %v, got %v", customErr, retryErr.LastError())
+	}
+
+	var status *statusErr
+	if !errors.As(err, &status) || status.code != 503 {
+		t.Fatalf("errors.As should match the error of the first attempt")
+	}
+
+	if !strings.HasPrefix(err.Error(), "retry: 3 attempts in ") || !strings.HasSuffix(err.Error(), ": custom") {
+		t.Fatalf("Error message not expected, got %s", err.Error())
+	}
+}
+
+func Test_ErrorCancelContext(t *testing.T) {
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1000)
+
+	err := retries.Execute(ctx, func(ctx context.Context, attempt int) error {
+		ctxCancel()
+		return customErr
+	})
+
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, customErr) {
+		t.Fatalf("Error should match both context.Canceled and the callback error, got %v", err)
+	}
+
+	var retryErr *Error
+	if !errors.As(err, &retryErr) || retryErr.Cause() != context.Canceled {
+		t.Fatalf("Cause not equal, want: %v, got %v", context.Canceled, err)
+	}
+
+	// canceled before the first attempt
+	if err = retries.Execute(ctx, executeFn); err != context.Canceled {
+		t.Fatalf("Error not equal, want: %v, got %v", context.Canceled, err)
+	}
+}
+
+func Test_ErrorMaxErrors(t *testing.T) {
+
+	retries := New(maxErrors+10, nil)
+	retries.SetFixedBackOff(0)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return customErr
+	})
+
+	var retryErr *Error
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("*Error expected, got %T", err)
+	}
+
+	if retryErr.AttemptCount() != maxErrors+11 || len(retryErr.Attempts()) != maxErrors {
+		t.Fatalf("Attempts not expected, count: %d, kept: %d", retryErr.AttemptCount(), len(retryErr.Attempts()))
+	}
+}
+
+func Test_ErrorZeroValue(t *testing.T) {
+
+	var retryErr Error
+
+	if retryErr.LastError() != nil {
+		t.Fatalf("LastError not expected, got %v", retryErr.LastError())
+	}
+
+	if want := "retry: 0 attempts in 0s"; retryErr.Error() != want {
+		t.Fatalf("Error not equal, want: %q, got %q", want, retryErr.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Freeze locks this Retry against further mutation and computes the hash of its effective policy (see PolicyHash).
+// Setters panic on a frozen Retry, and later writes to the exported Backoff field are ignored. Must be called before
+// the Retry is shared between goroutines. A Retry created with NewWithOptions is already frozen.
+func (r *Retry) Freeze() {
+	if r.frozen {
+		return
+	}
+	r.frozen = true
+	r.backoff = r.Backoff
+	r.policyHash = hashPolicy(r)
+}
+
+// PolicyHash returns a stable hash (hex-encoded SHA-256) of the effective policy of a frozen Retry, empty if not
+// frozen. It is included in the published events and in Error, making it possible to correlate observed behavior
+// with an exact policy version. Hooks, predicates and other functions are not part of the hash, and custom
+// BackoffStrategy implementations are only identified by their type.
+func (r *Retry) PolicyHash() string {
+	return r.policyHash
+}
+
+func hashPolicy(r *Retry) string {
+	var b strings.Builder
+	for _, f := range describePolicy(r) {
+		writePolicyField(&b, f.name, f.value)
+	}
+	writePolicyField(&b, "cancellation", strconv.Itoa(int(r.cancel)))
+	writePolicyField(&b, "attempt deadline from backoff", strconv.FormatBool(r.headroom))
+	writePolicyField(&b, "stale after", r.staleAfter.String())
+	writePolicyField(&b, "hedge delay", r.hedgeDelay.String())
+	writePolicyField(&b, "max hedges", strconv.Itoa(r.maxHedges))
+	writePolicyField(&b, "planned latency", r.planLatency.String())
+	writePolicyField(&b, "stuck after", r.stuckAfter.String())
+	writePolicyField(&b, "sampling", strconv.FormatFloat(r.sampling, 'g', -1, 64))
+	for _, limit := range r.softLimits {
+		writePolicyField(&b, "soft limit", strconv.FormatFloat(limit, 'g', -1, 64))
+	}
+	if r.adaptive != nil {
+		writePolicyField(&b, "adaptive timeout factor", strconv.FormatFloat(r.adaptive.factor, 'g', -1, 64))
+		writePolicyField(&b, "adaptive timeout min", r.adaptive.min.String())
+		writePolicyField(&b, "adaptive timeout max", r.adaptive.max.String())
+	}
+	if r.throttle != nil {
+		writePolicyField(&b, "throttle k", strconv.FormatFloat(r.throttle.k, 'g', -1, 64))
+		writePolicyField(&b, "throttle window", (r.throttle.bucket * throttleBuckets).String())
+	}
+	if r.negative != nil {
+		writePolicyField(&b, "negative cache ttl", r.negative.ttl.String())
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func writePolicyField(b *strings.Builder, name string, value string) {
+	b.WriteString(name)
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
@@ -0,0 +1,145 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Freeze(t *testing.T) {
+
+	retries := New(3, nil)
+	retries.SetExponentialBackoff(100, 1000, 2)
+	if retries.PolicyHash() != "" {
+		t.Fatalf("Hash not expected before freeze")
+	}
+
+	retries.Freeze()
+	hash := retries.PolicyHash()
+	if len(hash) != 64 {
+		t.Fatalf("Hash not expected, got %q", hash)
+	}
+
+	// the same policy built with options has the same hash
+	same := NewWithOptions(WithRetries(3), WithExponentialBackoff(100*time.Millisecond, time.Second, 2))
+	if same.PolicyHash() != hash {
+		t.Fatalf("Hash not equal, want: %s, got %s", hash, same.PolicyHash())
+	}
+
+	other := NewWithOptions(WithRetries(4), WithExponentialBackoff(100*time.Millisecond, time.Second, 2))
+	if other.PolicyHash() == hash {
+		t.Fatalf("Hash of different policies not expected to be equal")
+	}
+	jittered := NewWithOptions(WithRetries(3), WithExponentialBackoff(100*time.Millisecond, time.Second, 2), WithHashedJitter("job", time.Second))
+	if jittered.PolicyHash() == hash {
+		t.Fatalf("Hash of different policies not expected to be equal")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Panic expected")
+		}
+	}()
+	retries.SetNumberOfRetries(5)
+}
+
+func Test_FreezePolicyHashSettings(t *testing.T) {
+
+	adaptive := func(factor float64, minTimeout, maxTimeout time.Duration) Option {
+		return WithAdaptiveAttemptTimeout(NewAdaptiveTimeout(factor, minTimeout, maxTimeout))
+	}
+	throttle := func(k float64, window time.Duration) Option {
+		return WithThrottle(NewAdaptiveThrottle(k, window))
+	}
+
+	// each case changes a single setting
+	cases := []struct {
+		name   string
+		before Option
+		after  Option
+	}{
+		{"retries", WithRetries(3), WithRetries(4)},
+		{"backoff", WithFixedBackOff(time.Second), WithExponentialBackoff(time.Second, time.Minute, 2)},
+		{"jitter", WithBackoffJitter(NoJitter, nil), WithBackoffJitter(FullJitter, nil)},
+		{"hashed jitter", WithHashedJitter("a", time.Second), WithHashedJitter("b", time.Second)},
+		{"attempt timeout", WithAttemptTimeout(time.Second), WithAttemptTimeout(2 * time.Second)},
+		{"max elapsed time", WithMaxElapsedTime(time.Minute), WithMaxElapsedTime(2 * time.Minute)},
+		{"cancellation", WithCancellationPolicy(CancellationDefault), WithCancellationPolicy(CancellationStrict)},
+		{"deadline from backoff", WithAttemptDeadlineFromBackoff(false), WithAttemptDeadlineFromBackoff(true)},
+		{"stale after", WithStaleAfter(time.Minute, nil), WithStaleAfter(2*time.Minute, nil)},
+		{"hedge delay", WithHedgeDelay(time.Second), WithHedgeDelay(2 * time.Second)},
+		{"max hedges", WithMaxHedges(1), WithMaxHedges(2)},
+		{"deadline planning", WithDeadlinePlanning(time.Second, nil), WithDeadlinePlanning(2*time.Second, nil)},
+		{"watchdog", WithWatchdog(time.Second, nil), WithWatchdog(2*time.Second, nil)},
+		{"sampling", WithSampling(1), WithSampling(0.5)},
+		{"soft limits", WithSoftLimits(nil, 0.5), WithSoftLimits(nil, 0.8)},
+		{"adaptive timeout", WithAttemptTimeout(0), adaptive(3, time.Millisecond, time.Second)},
+		{"adaptive timeout factor", adaptive(3, time.Millisecond, time.Second), adaptive(2, time.Millisecond, time.Second)},
+		{"adaptive timeout min", adaptive(3, time.Millisecond, time.Second), adaptive(3, 2*time.Millisecond, time.Second)},
+		{"adaptive timeout max", adaptive(3, time.Millisecond, time.Second), adaptive(3, time.Millisecond, 2*time.Second)},
+		{"throttle", WithThrottle(nil), throttle(2, time.Minute)},
+		{"throttle k", throttle(2, time.Minute), throttle(1.5, time.Minute)},
+		{"throttle window", throttle(2, time.Minute), throttle(2, 2*time.Minute)},
+		{"linear increment", WithLinearBackoff(time.Second, time.Second, time.Minute), WithLinearBackoff(time.Second, 10*time.Second, time.Minute)},
+		{"wrapped exponential", options(
+			WithExponentialBackoff(time.Second, time.Minute, 2), WithBackoffJitter(FullJitter, nil), WithHashedJitter("job", time.Second),
+		), options(
+			WithExponentialBackoff(time.Second, time.Minute, 3), WithBackoffJitter(EqualJitter, nil), WithHashedJitter("job", time.Second),
+		)},
+		{"tick backoff", WithBackoff(NewTickBackoff(NewFixedBackOff(time.Second), time.Second)), WithBackoff(NewTickBackoff(NewFixedBackOff(time.Hour), time.Second))},
+		{"tick", WithBackoff(NewTickBackoff(NewFixedBackOff(time.Second), time.Second)), WithBackoff(NewTickBackoff(NewFixedBackOff(time.Second), time.Minute))},
+		{"negative cache", WithNegativeCache(NewNegativeCache(time.Minute)), WithNegativeCache(NewNegativeCache(time.Hour))},
+	}
+
+	for _, c := range cases {
+		before := NewWithOptions(WithFixedBackOff(time.Second), c.before).PolicyHash()
+		after := NewWithOptions(WithFixedBackOff(time.Second), c.after).PolicyHash()
+		if before == after {
+			t.Fatalf("Hash not expected to be equal after changing the %s", c.name)
+		}
+	}
+}
+
+func Test_FreezePolicyHashInEvents(t *testing.T) {
+
+	var hashes []string
+	retries := NewWithOptions(
+		WithFixedBackOff(time.Millisecond),
+		WithPublisher(PublisherFunc(func(topic string, event any) {
+			switch e := event.(type) {
+			case AttemptEvent:
+				hashes = append(hashes, e.PolicyHash)
+			case ErrorEvent:
+				hashes = append(hashes, e.PolicyHash)
+			case GiveUpEvent:
+				hashes = append(hashes, e.PolicyHash)
+			}
+		}), ""),
+	)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	})
+	var retryErr *Error
+	if !errors.As(err, &retryErr) || retryErr.PolicyHash() != retries.PolicyHash() {
+		t.Fatalf("Hash not included in error, got %v", err)
+	}
+	if len(hashes) != 3 {
+		t.Fatalf("Events not equal, want: %d, got %d", 3, len(hashes))
+	}
+	for _, h := range hashes {
+		if h != retries.PolicyHash() {
+			t.Fatalf("Hash not equal, want: %s, got %s", retries.PolicyHash(), h)
+		}
+	}
+}
+
+// options combines the options into one
+func options(opts ...Option) Option {
+	return func(r *Retry) {
+		for _, opt := range opts {
+			opt(r)
+		}
+	}
+}
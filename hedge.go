@@ -0,0 +1,188 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// SetHedgeDelay Set the delay after which ExecuteHedged launches a parallel attempt if the previous ones did not
+// finish yet
+//
+// Deprecated: use NewWithOptions with WithHedgeDelay.
+func (r *Retry) SetHedgeDelay(delay time.Duration) {
+	r.apply(WithHedgeDelay(delay))
+}
+
+// SetMaxHedges Set the maximum number of parallel attempts launched by ExecuteHedged in addition to the first one
+//
+// Deprecated: use NewWithOptions with WithMaxHedges.
+func (r *Retry) SetMaxHedges(maxHedges int) {
+	r.apply(WithMaxHedges(maxHedges))
+}
+
+type hedgeResult struct {
+	attempt int
+	err     error
+}
+
+// ExecuteHedged Hedged execution for latency-sensitive, read-only calls. Instead of waiting for an attempt to fail,
+// a parallel attempt is launched after the hedge delay, and the following ones spaced by the backoff strategy, up to
+// the max hedges. The first successful result wins and the other attempts are canceled. When all in-flight attempts
+// fail, the next one is launched immediately.
+//
+// Errors of the losing attempts are reported through OnError; attempts canceled after a winner are not. The callback
+// must honor the cancellation of its context, ExecuteHedged does not wait for canceled attempts to return.
+//
+// NoRetryContext disables the hedges. The throttle is consulted before each attempt, and no attempt is launched after
+// the max elapsed time. The number of retries, the attempt deadline from backoff, stale inputs, BeforeRetry, hints,
+// Retry-After delays and soft limits don't apply.
+func (r *Retry) ExecuteHedged(ctx context.Context, callback func(ctx context.Context, attempt int) error) error {
+	started := r.now()
+	if r.counters != nil {
+		r.counters.executions.Add(1)
+	}
+
+	ctx, warnings := withWarnings(ctx)
+	reported := warnings.len()
+	sampled := r.sample()
+	attempts, err := r.executeHedged(ctx, callback, started, sampled)
+	r.finish(ctx, err, attempts, started, warnings.since(reported), sampled)
+	return err
+}
+
+func (r *Retry) executeHedged(ctx context.Context, callback func(ctx context.Context, attempt int) error, started time.Time, sampled bool) (int, error) {
+	hedgeCtx, cancel := context.WithCancel(withMemo(ctx))
+	defer cancel()
+
+	maxHedges := r.maxHedges
+	if maxHedges < 0 || IsNoRetry(ctx) {
+		maxHedges = 0
+	}
+
+	// buffered, so attempts finishing after the return don't block
+	results := make(chan hedgeResult, maxHedges+1)
+	failures := &Error{}
+	launched, pending := 0, 0
+
+	timeout := r.attemptTimeout()
+	attemptFn := r.chain(callback)
+	strategy := executionBackoffOf(r.backoffStrategy())
+
+	// canLaunch reports whether another attempt may be launched, or the reason it may not when not exhausted
+	canLaunch := func() (bool, error) {
+		if launched > maxHedges {
+			return false, nil
+		}
+		if r.maxElapsed > 0 && launched > 0 && r.since(started) >= r.maxElapsed {
+			return false, ErrMaxElapsedTime
+		}
+		return true, nil
+	}
+
+	allow := func() bool {
+		return r.throttle == nil || r.throttle.Allow()
+	}
+
+	launch := func() {
+		launched++
+		pending++
+		traced := sampled || launched > 1
+		if traced {
+			r.notifyAttempt(ctx, launched)
+		}
+		if r.counters != nil {
+			r.counters.attempts.Add(1)
+		}
+		go func(attempt int) {
+			attemptCtx := context.Context(hedgeCtx)
+			var end func(err error)
+			if r.tracer != nil && traced {
+				attemptCtx, end = r.tracer.StartAttempt(attemptCtx, attempt)
+			}
+			err := r.runAttempt(attemptCtx, attempt, timeout, attemptFn)
+			if end != nil {
+				end(err)
+			}
+			results <- hedgeResult{attempt: attempt, err: err}
+		}(launched)
+	}
+
+	// the hedge timer waits on the Clock, each reset replaces the channel so a stale expiry is never observed
+	var timer <-chan struct{}
+	stopTimer := func() {}
+	defer func() { stopTimer() }()
+	resetTimer := func(d time.Duration) {
+		stopTimer()
+		timer = nil
+		if launched > maxHedges {
+			return
+		}
+		timerCtx, stop := context.WithCancel(hedgeCtx)
+		expired := make(chan struct{})
+		go func() {
+			if r.hedgeWait(timerCtx, d) == nil {
+				close(expired)
+			}
+		}()
+		timer, stopTimer = expired, stop
+	}
+
+	if !allow() {
+		r.notifyError(ctx, ErrThrottled, 1, false, time.Duration(0))
+		return 1, ErrThrottled
+	}
+	launch()
+	resetTimer(r.hedgeDelay)
+	for {
+		select {
+		case <-ctx.Done():
+			return launched, failures.abort(ctx.Err(), r.since(started))
+		case <-timer:
+			if ok, _ := canLaunch(); ok && allow() {
+				launch()
+				resetTimer(strategy.NextDelay(launched, nil))
+			} else {
+				// no more hedges, waits for the in-flight attempts
+				stopTimer()
+				timer = nil
+			}
+		case res := <-results:
+			pending--
+			if r.throttle != nil {
+				r.throttle.Record(res.err == nil)
+			}
+			if res.err == nil {
+				return launched, nil
+			}
+			failures.add(res.err)
+
+			retryable := r.isRetryable(res.err)
+			next, cause := canLaunch()
+			more := retryable && (pending > 0 || next)
+			// all in-flight attempts failed, the next one is launched immediately
+			relaunch := more && pending == 0
+			if relaunch && !allow() {
+				relaunch, more, cause = false, false, ErrThrottled
+			}
+			r.notifyError(ctx, res.err, res.attempt, more, time.Duration(0))
+			if !more {
+				if !retryable {
+					cause = nil
+				}
+				return launched, failures.abort(cause, r.since(started))
+			}
+			if relaunch {
+				launch()
+				resetTimer(strategy.NextDelay(launched, nil))
+			}
+		}
+	}
+}
+
+// hedgeWait waits for the hedge delay on the Clock, or on a runtime timer
+func (r *Retry) hedgeWait(ctx context.Context, d time.Duration) error {
+	if r.clock != nil {
+		return r.clock.Wait(ctx, d)
+	}
+	return TimerWaiter{}.Wait(ctx, d)
+}
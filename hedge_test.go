@@ -0,0 +1,175 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteHedged(t *testing.T) {
+	verifyNoLeaks(t)
+
+	var canceled int32
+
+	retries := New(0, nil)
+	retries.SetFixedBackOff(5)
+	retries.SetHedgeDelay(5 * time.Millisecond)
+	retries.SetMaxHedges(2)
+
+	start := time.Now()
+	err := retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			// slow attempt
+			<-ctx.Done()
+			atomic.AddInt32(&canceled, 1)
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Hedge not launched, elapsed: %s", elapsed)
+	}
+
+	if stats := retries.Stats(); stats.Attempts != 2 || stats.Successes != 1 {
+		t.Fatalf("Stats not expected, got %+v", stats)
+	}
+
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&canceled) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Losing attempt not canceled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_ExecuteHedgedAllFail(t *testing.T) {
+
+	var reported []int
+
+	retries := New(0, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		reported = append(reported, attempt)
+	})
+	retries.SetFixedBackOff(1000)
+	retries.SetHedgeDelay(time.Second)
+	retries.SetMaxHedges(2)
+
+	err := retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+		return customErr
+	})
+
+	var retryErr *Error
+	if !errors.As(err, &retryErr) || retryErr.AttemptCount() != 3 {
+		t.Fatalf("*Error with 3 attempts expected, got %v", err)
+	}
+
+	// failed attempts launch the next one immediately
+	if len(reported) != 3 {
+		t.Fatalf("Reported errors not equal, want: %d, got %d", 3, len(reported))
+	}
+}
+
+func Test_ExecuteHedgedUnrecoverable(t *testing.T) {
+
+	calls := int32(0)
+
+	retries := New(0, nil)
+	retries.SetHedgeDelay(time.Second)
+	retries.SetMaxHedges(2)
+
+	err := retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+		atomic.AddInt32(&calls, 1)
+		return Unrecoverable(customErr)
+	})
+
+	if !errors.Is(err, ErrUnrecoverable) || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("Unrecoverable error expected after 1 call, got %v (%d calls)", err, calls)
+	}
+}
+
+func Test_ExecuteHedgedTracer(t *testing.T) {
+
+	var started, ended int32
+
+	retries := NewWithOptions(
+		WithFixedBackOff(time.Millisecond),
+		WithHedgeDelay(time.Hour),
+		WithMaxHedges(2),
+		WithTracer(TracerFunc(func(ctx context.Context, attempt int) (context.Context, func(err error)) {
+			atomic.AddInt32(&started, 1)
+			return ctx, func(err error) { atomic.AddInt32(&ended, 1) }
+		})),
+	)
+
+	err := retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt < 3 {
+			return customErr
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	if atomic.LoadInt32(&started) != 3 || atomic.LoadInt32(&ended) != 3 {
+		t.Fatalf("Spans not equal, want: %d, started: %d, ended: %d", 3, started, ended)
+	}
+}
+
+func Test_ExecuteHedgedNoRetry(t *testing.T) {
+
+	var calls int32
+
+	for _, ctx := range []context.Context{NoRetryContext(context.Background()), context.Background()} {
+		calls = 0
+		maxHedges := 2
+		if !IsNoRetry(ctx) {
+			// below -1, clamped to no hedges
+			maxHedges = -5
+		}
+		retries := NewWithOptions(WithHedgeDelay(time.Millisecond), WithMaxHedges(maxHedges))
+
+		err := retries.ExecuteHedged(ctx, func(ctx context.Context, attempt int) error {
+			atomic.AddInt32(&calls, 1)
+			return customErr
+		})
+
+		if !errors.Is(err, customErr) {
+			t.Fatalf("Error not equal, want: %v, got %v", customErr, err)
+		}
+		if n := atomic.LoadInt32(&calls); n != 1 {
+			t.Fatalf("Count calls not equal, want: %d, got %d", 1, n)
+		}
+	}
+}
+
+func Test_ExecuteHedgedMaxElapsedTime(t *testing.T) {
+
+	var calls int32
+
+	retries := NewWithOptions(
+		WithFixedBackOff(time.Hour),
+		WithHedgeDelay(time.Hour),
+		WithMaxHedges(5),
+		WithMaxElapsedTime(20*time.Millisecond),
+	)
+
+	err := retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(15 * time.Millisecond)
+		return customErr
+	})
+
+	if !errors.Is(err, ErrMaxElapsedTime) {
+		t.Fatalf("Error not equal, want: %v, got %v", ErrMaxElapsedTime, err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 2, n)
+	}
+}
@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Retryability classification carried by a Hint
+type Retryability int
+
+const (
+	RetryUnknown   Retryability = iota // the hint does not classify the error
+	RetryAllowed                       // the error is transient
+	RetryForbidden                     // the error is permanent, retrying is pointless
+)
+
+// Hint retry coordination hint extracted from an error returned by the server
+type Hint struct {
+	Delay     time.Duration // requested delay before the next attempt, when > 0
+	Retryable Retryability
+}
+
+// HintExtractor extracts a Hint from an error, returning false if the error carries no hint
+type HintExtractor func(err error) (Hint, bool)
+
+// PayloadError is implemented by errors that carry the raw payload returned by the server
+type PayloadError interface {
+	error
+	Payload() []byte
+}
+
+// SetHintExtractors Set the extractors of retry hints from errors. The first hint found overrides the delay computed
+// by the BackoffStrategy and, when it classifies the error, the SetRetryIf predicate.
+//
+// Deprecated: use NewWithOptions with WithHintExtractors.
+func (r *Retry) SetHintExtractors(extractors ...HintExtractor) {
+	r.apply(WithHintExtractors(extractors...))
+}
+
+func (r *Retry) hint(err error) (Hint, bool) {
+	for _, extract := range r.hints {
+		if h, ok := extract(err); ok {
+			return h, true
+		}
+	}
+	return Hint{}, false
+}
+
+// JSONHint extracts hints from JSON payloads (see PayloadError) in the form
+//
+//	{"retry_after_ms": 1500, "retryable": true}
+func JSONHint(err error) (Hint, bool) {
+	var payloadErr PayloadError
+	if !errors.As(err, &payloadErr) {
+		return Hint{}, false
+	}
+
+	var payload struct {
+		RetryAfterMs *int64 `json:"retry_after_ms"`
+		Retryable    *bool  `json:"retryable"`
+	}
+	if json.Unmarshal(payloadErr.Payload(), &payload) != nil {
+		return Hint{}, false
+	}
+	if payload.RetryAfterMs == nil && payload.Retryable == nil {
+		return Hint{}, false
+	}
+
+	h := Hint{}
+	if payload.RetryAfterMs != nil {
+		h.Delay = time.Duration(*payload.RetryAfterMs) * time.Millisecond
+	}
+	if payload.Retryable != nil {
+		h.Retryable = RetryForbidden
+		if *payload.Retryable {
+			h.Retryable = RetryAllowed
+		}
+	}
+	return h, true
+}
+
+// smtp enhanced status code (RFC 3463) following the reply code, e.g. "451 4.2.2", so that IP addresses such as
+// "10.5.0.1" in dial errors are not mistaken for one, or basic reply code, e.g. "451"
+var (
+	smtpEnhanced = regexp.MustCompile(`\b[245]\d\d[ -]([245])\.\d{1,3}\.\d{1,3}\b`)
+	smtpReply    = regexp.MustCompile(`^([245])\d\d[ -]`)
+)
+
+// SMTPHint classifies errors by their SMTP enhanced status code (RFC 3463) or reply code: 4.x.x/4xx are transient,
+// 5.x.x/5xx are permanent.
+func SMTPHint(err error) (Hint, bool) {
+	msg := err.Error()
+	class := ""
+	if m := smtpEnhanced.FindStringSubmatch(msg); m != nil {
+		class = m[1]
+	} else if m = smtpReply.FindStringSubmatch(msg); m != nil {
+		class = m[1]
+	}
+
+	switch class {
+	case "4":
+		return Hint{Retryable: RetryAllowed}, true
+	case "5":
+		return Hint{Retryable: RetryForbidden}, true
+	}
+	return Hint{}, false
+}
+
+// DelayHint extracts the delay from errors implementing RetryDelay() time.Duration, the shape of gRPC RetryInfo
+// details once unpacked by the application
+func DelayHint(err error) (Hint, bool) {
+	var delayErr interface {
+		error
+		RetryDelay() time.Duration
+	}
+	if !errors.As(err, &delayErr) {
+		return Hint{}, false
+	}
+	return Hint{Delay: delayErr.RetryDelay(), Retryable: RetryAllowed}, true
+}
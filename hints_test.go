@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type payloadErr struct {
+	payload string
+}
+
+func (e *payloadErr) Error() string {
+	return "server error"
+}
+
+func (e *payloadErr) Payload() []byte {
+	return []byte(e.payload)
+}
+
+type retryInfoErr struct{}
+
+func (e *retryInfoErr) Error() string {
+	return "unavailable"
+}
+
+func (e *retryInfoErr) RetryDelay() time.Duration {
+	return 7 * time.Second
+}
+
+func Test_Hints(t *testing.T) {
+
+	cases := []struct {
+		extractor HintExtractor
+		err       error
+		want      Hint
+		found     bool
+	}{
+		{JSONHint, &payloadErr{`{"retry_after_ms": 1500}`}, Hint{Delay: 1500 * time.Millisecond}, true},
+		{JSONHint, &payloadErr{`{"retryable": false}`}, Hint{Retryable: RetryForbidden}, true},
+		{JSONHint, &payloadErr{`{"message": "oops"}`}, Hint{}, false},
+		{JSONHint, &payloadErr{`<html>`}, Hint{}, false},
+		{SMTPHint, errors.New("451 4.7.1 Greylisted, try again later"), Hint{Retryable: RetryAllowed}, true},
+		{SMTPHint, errors.New("550 5.1.1 User unknown"), Hint{Retryable: RetryForbidden}, true},
+		{SMTPHint, errors.New("421 Service not available"), Hint{Retryable: RetryAllowed}, true},
+		{SMTPHint, errors.New("connection reset"), Hint{}, false},
+		{SMTPHint, errors.New("dial tcp 10.5.0.1:25: connect: connection refused"), Hint{}, false},
+		{SMTPHint, errors.New("smtp: 550-5.7.1 Rejected from 10.4.0.1"), Hint{Retryable: RetryForbidden}, true},
+		{DelayHint, &retryInfoErr{}, Hint{Delay: 7 * time.Second, Retryable: RetryAllowed}, true},
+	}
+
+	for _, c := range cases {
+		got, found := c.extractor(c.err)
+		if found != c.found || got != c.want {
+			t.Fatalf("Hint of %q not equal, want: %+v (%v), got %+v (%v)", c.err, c.want, c.found, got, found)
+		}
+	}
+}
+
+func Test_HintsPipeline(t *testing.T) {
+
+	var waits []time.Duration
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(100)
+	retries.SetHintExtractors(JSONHint, SMTPHint)
+	retries.SetWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}))
+
+	calls := 0
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		calls++
+		switch attempt {
+		case 1:
+			return &payloadErr{`{"retry_after_ms": 1500}`}
+		case 2:
+			return errors.New("451 4.7.1 Greylisted")
+		}
+		return errors.New("550 5.1.1 User unknown")
+	})
+
+	if err == nil {
+		t.Fatalf("Error expected")
+	}
+
+	if calls != 3 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 3, calls)
+	}
+
+	if len(waits) != 2 || waits[0] != 1500*time.Millisecond || waits[1] != 100*time.Millisecond {
+		t.Fatalf("Waits not expected, got %v", waits)
+	}
+}
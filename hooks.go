@@ -0,0 +1,36 @@
+package retry
+
+import "context"
+
+// IdleConnectionsCloser is implemented by *http.Transport and *http.Client
+type IdleConnectionsCloser interface {
+	CloseIdleConnections()
+}
+
+// CloseIdleConnections returns a BeforeRetry that drops the idle pooled connections of the given transport, so the
+// next attempt dials a fresh connection (and re-resolves DNS).
+func CloseIdleConnections(transport IdleConnectionsCloser) BeforeRetry {
+	return func(ctx context.Context, err error, attempt int) error {
+		transport.CloseIdleConnections()
+		return nil
+	}
+}
+
+// RefreshToken returns a BeforeRetry that invokes the given refresher before each retry attempt
+func RefreshToken(refresh func(ctx context.Context) error) BeforeRetry {
+	return func(ctx context.Context, err error, attempt int) error {
+		return refresh(ctx)
+	}
+}
+
+// ChainBeforeRetry combines multiple BeforeRetry hooks, executed in order. The first error aborts the chain.
+func ChainBeforeRetry(hooks ...BeforeRetry) BeforeRetry {
+	return func(ctx context.Context, err error, attempt int) error {
+		for _, hook := range hooks {
+			if hookErr := hook(ctx, err, attempt); hookErr != nil {
+				return hookErr
+			}
+		}
+		return nil
+	}
+}
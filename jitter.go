@@ -0,0 +1,163 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// JitterMode randomization applied to the delays of a BackoffStrategy, avoiding thundering-herd retries when many
+// clients fail at the same moment.
+type JitterMode int
+
+const (
+	// NoJitter uses the delay as is
+	NoJitter JitterMode = iota
+	// FullJitter random delay in [0, delay)
+	FullJitter
+	// EqualJitter random delay in [delay/2, delay)
+	EqualJitter
+	// DecorrelatedJitter random delay in [base, 3 * previous delay), where base is the delay of the first attempt and
+	// the previous delay is the last jittered one of the execution. Bounded by the maximum delay of the wrapped
+	// strategy when known, otherwise by 3 times its delay for the attempt.
+	DecorrelatedJitter
+)
+
+// JitterBackoffStrategy A BackoffStrategy that randomizes the delays of another strategy
+type JitterBackoffStrategy struct {
+	strategy BackoffStrategy
+	mode     JitterMode
+	mu       sync.Mutex
+	random   *rand.Rand
+	stateMu  sync.Mutex
+	state    decorrelatedState // DecorrelatedJitter state, when used outside an execution
+}
+
+// WithJitter wraps the strategy with the given jitter mode. The random source is optional, allowing deterministic
+// tests; it is safe to share the strategy across goroutines.
+func WithJitter(strategy BackoffStrategy, mode JitterMode, random *rand.Rand) *JitterBackoffStrategy {
+	return &JitterBackoffStrategy{strategy: strategy, mode: mode, random: random}
+}
+
+func (b *JitterBackoffStrategy) NextDelay(attempt int, err error) time.Duration {
+	delay := float64(b.strategy.NextDelay(attempt, err))
+	switch b.mode {
+	case FullJitter:
+		return time.Duration(b.float64() * delay)
+	case EqualJitter:
+		return time.Duration(delay/2 + b.float64()*delay/2)
+	case DecorrelatedJitter:
+		b.stateMu.Lock()
+		defer b.stateMu.Unlock()
+		return b.decorrelated(&b.state, attempt, err)
+	}
+	return time.Duration(delay)
+}
+
+// decorrelatedState the previous DecorrelatedJitter delays
+type decorrelatedState struct {
+	attempt int
+	prev    time.Duration // delay of the previous attempt
+	delay   time.Duration // delay of the current attempt
+}
+
+// decorrelated computes min(cap, random[base, 3 * prev)), the first attempt starts from base. The delay of an attempt
+// may be computed more than once (ahead of time, then with the error), each time from the same previous delay.
+func (b *JitterBackoffStrategy) decorrelated(state *decorrelatedState, attempt int, err error) time.Duration {
+	base := b.strategy.NextDelay(1, err)
+	if attempt != state.attempt {
+		state.attempt = attempt
+		state.prev = state.delay
+		if attempt <= 1 || state.prev <= 0 {
+			state.prev = base
+		}
+	}
+	d := time.Duration(float64(base) + b.float64()*math.Max(0, float64(3*state.prev-base)))
+	maxDelay, ok := maxDelayOf(b.strategy)
+	if !ok {
+		maxDelay = 3 * b.strategy.NextDelay(attempt, err)
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	state.delay = d
+	return d
+}
+
+// forExecution returns a strategy tracking the previous DecorrelatedJitter delay of a single execution, so concurrent
+// executions sharing this strategy don't interfere
+func (b *JitterBackoffStrategy) forExecution() BackoffStrategy {
+	if b.mode != DecorrelatedJitter {
+		return b
+	}
+	return &decorrelatedJitter{jitter: b}
+}
+
+// decorrelatedJitter the DecorrelatedJitter state of an execution
+type decorrelatedJitter struct {
+	jitter *JitterBackoffStrategy
+	state  decorrelatedState
+}
+
+func (d *decorrelatedJitter) NextDelay(attempt int, err error) time.Duration {
+	return d.jitter.decorrelated(&d.state, attempt, err)
+}
+
+// executionBackoff is implemented by the strategies with state for each execution
+type executionBackoff interface {
+	forExecution() BackoffStrategy
+}
+
+// executionBackoffOf returns the strategy used by a single execution
+func executionBackoffOf(strategy BackoffStrategy) BackoffStrategy {
+	if s, ok := strategy.(executionBackoff); ok {
+		return s.forExecution()
+	}
+	return strategy
+}
+
+func (b *JitterBackoffStrategy) float64() float64 {
+	if b.random == nil {
+		return rand.Float64()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.random.Float64()
+}
+
+// maxDelayOf returns the maximum delay of the known strategies with a cap
+func maxDelayOf(strategy BackoffStrategy) (time.Duration, bool) {
+	switch s := strategy.(type) {
+	case *ExponentialBackoffStrategy:
+		return s.maxTime, true
+	case *LinearBackoffStrategy:
+		return s.maxTime, true
+	case *FibonacciBackoffStrategy:
+		return s.maxTime, true
+	}
+	return 0, false
+}
+
+// SetExponentialBackoffWithJitter same as SetExponentialBackoff, with the given jitter mode
+//
+// Deprecated: use NewWithOptions with WithExponentialBackoff and WithBackoffJitter.
+func (r *Retry) SetExponentialBackoffWithJitter(initTime int, maxTime int, factor float64, mode JitterMode) {
+	r.SetExponentialBackoff(initTime, maxTime, factor)
+	r.apply(WithBackoffJitter(mode, nil))
+}
+
+func (m JitterMode) String() string {
+	switch m {
+	case NoJitter:
+		return "none"
+	case FullJitter:
+		return "full"
+	case EqualJitter:
+		return "equal"
+	case DecorrelatedJitter:
+		return "decorrelated"
+	}
+	return "JitterMode(" + strconv.Itoa(int(m)) + ")"
+}
@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func Test_Jitter(t *testing.T) {
+
+	exponential := NewExponentialBackoff(500*time.Millisecond, 5*time.Second, 2)
+
+	full := WithJitter(exponential, FullJitter, rand.New(rand.NewSource(1)))
+	equal := WithJitter(exponential, EqualJitter, rand.New(rand.NewSource(1)))
+	decorrelated := WithJitter(exponential, DecorrelatedJitter, rand.New(rand.NewSource(1)))
+
+	for attempt := 1; attempt <= 8; attempt++ {
+		delay := exponential.NextDelay(attempt, nil)
+
+		if d := full.NextDelay(attempt, nil); d < 0 || d >= delay {
+			t.Fatalf("FullJitter out of range [0, %s), got %s", delay, d)
+		}
+
+		if d := equal.NextDelay(attempt, nil); d < delay/2 || d >= delay {
+			t.Fatalf("EqualJitter out of range [%s, %s), got %s", delay/2, delay, d)
+		}
+
+		if d := decorrelated.NextDelay(attempt, nil); d < 500*time.Millisecond || d > 5*time.Second {
+			t.Fatalf("DecorrelatedJitter out of range [500ms, 5s], got %s", d)
+		}
+	}
+
+	if d := WithJitter(exponential, NoJitter, nil).NextDelay(3, nil); d != 2*time.Second {
+		t.Fatalf("NoJitter not equal, want: %s, got %s", 2*time.Second, d)
+	}
+}
+
+func Test_JitterDeterministic(t *testing.T) {
+
+	a := WithJitter(NewFixedBackOff(time.Second), FullJitter, rand.New(rand.NewSource(42)))
+	b := WithJitter(NewFixedBackOff(time.Second), FullJitter, rand.New(rand.NewSource(42)))
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		if a.NextDelay(attempt, nil) != b.NextDelay(attempt, nil) {
+			t.Fatalf("Jitter with the same source should be deterministic")
+		}
+	}
+}
+
+func Test_DecorrelatedJitterFixed(t *testing.T) {
+
+	var waits []time.Duration
+
+	retries := NewWithOptions(
+		WithRetries(10),
+		WithFixedBackOff(100*time.Millisecond),
+		WithBackoffJitter(DecorrelatedJitter, rand.New(rand.NewSource(1))),
+		WithWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		})),
+	)
+
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return customErr
+	})
+
+	if len(waits) != 10 {
+		t.Fatalf("Waits not equal, want: %d, got %d", 10, len(waits))
+	}
+
+	distinct := map[time.Duration]bool{}
+	for _, d := range waits {
+		if d < 100*time.Millisecond || d > 300*time.Millisecond {
+			t.Fatalf("DecorrelatedJitter out of range [100ms, 300ms], got %s", d)
+		}
+		distinct[d] = true
+	}
+	if len(distinct) < 5 {
+		t.Fatalf("DecorrelatedJitter should spread the delays of a Fixed strategy, got %v", waits)
+	}
+}
+
+func Test_DecorrelatedJitterPrevious(t *testing.T) {
+
+	// with a random source always at the top of the range, each delay is 3 times the previous one
+	strategy := WithJitter(NewExponentialBackoff(time.Second, time.Hour, 1), DecorrelatedJitter, rand.New(constSource(1<<63-1<<20)))
+
+	execution := executionBackoffOf(strategy)
+	prev := time.Second
+	for attempt := 1; attempt <= 4; attempt++ {
+		d := execution.NextDelay(attempt, nil)
+		if want := 3 * prev; d < want-time.Millisecond || d > want {
+			t.Fatalf("Delay of attempt %d not equal, want: ~%s, got %s", attempt, want, d)
+		}
+		// computing the delay of the same attempt again starts from the same previous delay
+		if again := execution.NextDelay(attempt, nil); again < 3*prev-time.Millisecond || again > 3*prev {
+			t.Fatalf("Delay of attempt %d computed again not equal, want: ~%s, got %s", attempt, 3*prev, again)
+		}
+		prev = d
+	}
+}
+
+// constSource a rand.Source always returning the same value
+type constSource int64
+
+func (s constSource) Int63() int64 { return int64(s) }
+func (s constSource) Seed(int64)   {}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// Journal records side effects already applied, enabling at-most-once semantics for non-idempotent steps inside
+// retried operations.
+type Journal interface {
+	// Applied reports whether the side effect identified by key was already applied
+	Applied(ctx context.Context, key string) (bool, error)
+	// Record marks the side effect identified by key as applied
+	Record(ctx context.Context, key string) error
+}
+
+// MemoryJournal A Journal backed by an in-memory set, safe for concurrent use.
+type MemoryJournal struct {
+	mu      sync.Mutex
+	applied map[string]struct{}
+}
+
+// NewMemoryJournal initialize new MemoryJournal
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{applied: map[string]struct{}{}}
+}
+
+func (j *MemoryJournal) Applied(ctx context.Context, key string) (bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, ok := j.applied[key]
+	return ok, nil
+}
+
+func (j *MemoryJournal) Record(ctx context.Context, key string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.applied[key] = struct{}{}
+	return nil
+}
+
+// ApplyOnce runs the side effect identified by key only if the journal has no record of it, recording it on success.
+// Returns whether the effect was already applied by a previous attempt.
+func ApplyOnce(ctx context.Context, journal Journal, key string, effect func(ctx context.Context) error) (bool, error) {
+	applied, err := journal.Applied(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if applied {
+		return true, nil
+	}
+	if err = effect(ctx); err != nil {
+		return false, err
+	}
+	return false, journal.Record(ctx, key)
+}
@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_ApplyOnce(t *testing.T) {
+
+	journal := NewMemoryJournal()
+	countCharge := 0
+	var alreadyApplied []bool
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		applied, err := ApplyOnce(ctx, journal, "charge:42", func(ctx context.Context) error {
+			countCharge++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		alreadyApplied = append(alreadyApplied, applied)
+
+		if attempt < 3 {
+			return customErr
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if countCharge != 1 {
+		t.Fatalf("Count charge not equal, want: %d, got %d", 1, countCharge)
+	}
+
+	if len(alreadyApplied) != 3 || alreadyApplied[0] || !alreadyApplied[1] || !alreadyApplied[2] {
+		t.Fatalf("Applied not equal, want: %v, got %v", []bool{false, true, true}, alreadyApplied)
+	}
+}
@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// verifyNoLeaks fails the test if goroutines started during the test are still running when it ends
+func verifyNoLeaks(t *testing.T) {
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				t.Fatalf("Goroutine leak, before: %d, after %d\n%s", before, runtime.NumGoroutine(), buf[:runtime.Stack(buf, true)])
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+func Test_NoLeakCancelDuringSleep(t *testing.T) {
+	verifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	retries := New(-1, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		go cancel()
+	})
+	retries.SetFixedBackOff(60000)
+
+	if err := retries.Execute(ctx, executeFn); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error not equal, want: %v, got %v", context.Canceled, err)
+	}
+}
+
+func Test_NoLeakSemaphoreTimeout(t *testing.T) {
+	verifyNoLeaks(t)
+
+	sem := NewSemaphore(1, time.Millisecond, time.Millisecond)
+	ctx := context.Background()
+
+	_ = sem.Acquire(ctx, "write", 1, 1)
+	for i := 0; i < 10; i++ {
+		if err := sem.Acquire(ctx, "write", 1, 2); err != ErrSemaphoreTimeout {
+			t.Fatalf("Error not equal, want: %v, got %v", ErrSemaphoreTimeout, err)
+		}
+	}
+}
+
+func Test_NoLeakHedgeLosers(t *testing.T) {
+	verifyNoLeaks(t)
+
+	retries := NewWithOptions(
+		WithFixedBackOff(time.Millisecond),
+		WithHedgeDelay(time.Millisecond),
+		WithMaxHedges(3),
+	)
+
+	err := retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt < 3 {
+			// slow attempts, canceled once the hedge wins
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+}
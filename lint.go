@@ -0,0 +1,56 @@
+package retry
+
+import "fmt"
+
+// Lint warning codes
+const (
+	LintUnlimitedRetries = "unlimited-retries" // unlimited retries with no bound on the total elapsed time
+	LintNoJitter         = "no-jitter"         // exponential backoff without jitter causes thundering-herd retries
+	LintNoBackoff        = "no-backoff"        // retries without any wait between attempts
+)
+
+var noJitterWarning = LintWarning{
+	Code:    LintNoJitter,
+	Message: "exponential backoff without jitter synchronizes retries across clients",
+}
+
+// LintWarning A foot-gun detected in a Retry configuration
+type LintWarning struct {
+	Code    string
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return w.Code + ": " + w.Message
+}
+
+// Lint returns warnings for foot-guns in the configuration of the given Retry. Usable in unit tests to enforce
+// organizational retry standards. See retryhttp.Lint for the retried HTTP methods.
+func Lint(r *Retry) []LintWarning {
+	var warnings []LintWarning
+
+	if r.unlimited && r.maxElapsed <= 0 {
+		warnings = append(warnings, LintWarning{
+			Code:    LintUnlimitedRetries,
+			Message: "unlimited retries without max elapsed time will never give up",
+		})
+	}
+
+	switch b := r.backoffStrategy().(type) {
+	case *FixedBackOffStrategy:
+		if b.period <= 0 {
+			warnings = append(warnings, LintWarning{
+				Code:    LintNoBackoff,
+				Message: fmt.Sprintf("fixed backoff of %s retries in a hot loop", b.period),
+			})
+		}
+	case *ExponentialBackoffStrategy:
+		warnings = append(warnings, noJitterWarning)
+	case *JitterBackoffStrategy:
+		if _, ok := b.strategy.(*ExponentialBackoffStrategy); ok && b.mode == NoJitter {
+			warnings = append(warnings, noJitterWarning)
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,46 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Lint(t *testing.T) {
+
+	retries := New(-1, nil)
+	retries.SetExponentialBackoff(500, 5000, 2)
+
+	warnings := Lint(retries)
+
+	if len(warnings) != 2 {
+		t.Fatalf("Warnings count not equal, want: %d, got %d (%v)", 2, len(warnings), warnings)
+	}
+
+	if warnings[0].Code != LintUnlimitedRetries || warnings[1].Code != LintNoJitter {
+		t.Fatalf("Warnings not equal, got %v", warnings)
+	}
+
+	retries = New(3, nil)
+	retries.SetFixedBackOff(0)
+
+	warnings = Lint(retries)
+	if len(warnings) != 1 || warnings[0].Code != LintNoBackoff {
+		t.Fatalf("Warnings not equal, got %v", warnings)
+	}
+
+	retries = New(3, nil)
+	retries.SetExponentialBackoffWithJitter(500, 5000, 2, FullJitter)
+	if warnings = Lint(retries); len(warnings) != 0 {
+		t.Fatalf("Warnings not expected, got %v", warnings)
+	}
+
+	retries = New(-1, nil)
+	retries.SetMaxElapsedTime(time.Minute)
+	if warnings = Lint(retries); len(warnings) != 0 {
+		t.Fatalf("Warnings not expected, got %v", warnings)
+	}
+
+	if warnings = Lint(New(3, nil)); len(warnings) != 0 {
+		t.Fatalf("Warnings not expected, got %v", warnings)
+	}
+}
@@ -0,0 +1,26 @@
+package retry
+
+import "context"
+
+// AttemptFunc A single attempt of an execution
+type AttemptFunc func(ctx context.Context, attempt int) error
+
+// Middleware wraps every attempt, so cross-cutting concerns (auth refresh, metrics, header injection, chaos) compose
+// as an ordered chain. It runs inside the attempt context, after the attempt timeout is applied.
+type Middleware func(next AttemptFunc) AttemptFunc
+
+// WithMiddleware appends middlewares to the chain applied around every attempt. The first middleware is the
+// outermost one.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(r *Retry) {
+		r.middlewares = append(r.middlewares[:len(r.middlewares):len(r.middlewares)], middlewares...)
+	}
+}
+
+// chain wraps the callback with the middlewares
+func (r *Retry) chain(callback AttemptFunc) AttemptFunc {
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		callback = r.middlewares[i](callback)
+	}
+	return callback
+}
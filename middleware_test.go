@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type middlewareKey struct{}
+
+func Test_Middleware(t *testing.T) {
+
+	var events []string
+	trace := func(name string) Middleware {
+		return func(next AttemptFunc) AttemptFunc {
+			return func(ctx context.Context, attempt int) error {
+				events = append(events, name+"-"+strconv.Itoa(attempt))
+				return next(ctx, attempt)
+			}
+		}
+	}
+	inject := func(next AttemptFunc) AttemptFunc {
+		return func(ctx context.Context, attempt int) error {
+			return next(context.WithValue(ctx, middlewareKey{}, "injected"), attempt)
+		}
+	}
+
+	retries := NewWithOptions(
+		WithRetries(3),
+		WithFixedBackOff(time.Millisecond),
+		WithAttemptTimeout(time.Second),
+		WithMiddleware(trace("outer"), trace("inner")),
+		WithMiddleware(inject),
+	)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if ctx.Value(middlewareKey{}) != "injected" {
+			t.Fatalf("Value not injected")
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Fatalf("Attempt timeout not applied")
+		}
+		if attempt < 2 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+
+	want := []string{"outer-1", "inner-1", "outer-2", "inner-2"}
+	if len(events) != len(want) {
+		t.Fatalf("Events not equal, want: %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("Events not equal, want: %v, got %v", want, events)
+		}
+	}
+}
+
+func Test_MiddlewareShortCircuit(t *testing.T) {
+
+	errChaos := errors.New("chaos")
+	chaos := func(next AttemptFunc) AttemptFunc {
+		return func(ctx context.Context, attempt int) error {
+			if attempt == 1 {
+				return errChaos
+			}
+			return next(ctx, attempt)
+		}
+	}
+
+	var failures []error
+	retries := NewWithOptions(
+		WithRetries(1),
+		WithFixedBackOff(time.Millisecond),
+		WithMiddleware(chaos),
+		WithOnError(func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			failures = append(failures, err)
+		}),
+	)
+
+	calls := 0
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Calls not equal, want: %d, got %d", 1, calls)
+	}
+	if len(failures) != 1 || failures[0] != errChaos {
+		t.Fatalf("Failures not expected, got %v", failures)
+	}
+}
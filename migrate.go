@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+var ErrNotApplied = errors.New("retry: statement not applied")
+
+type notAppliedError struct {
+	err error
+}
+
+func (e *notAppliedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *notAppliedError) Unwrap() error {
+	return e.err
+}
+
+func (e *notAppliedError) Is(target error) bool {
+	return target == ErrNotApplied
+}
+
+// NotApplied wraps an error known to have happened before the statement of a migration step had any effect (lock or
+// connection timeout), allowing a non-idempotent step to be retried. Detectable with errors.Is(err, ErrNotApplied).
+func NotApplied(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &notAppliedError{err: err}
+}
+
+// MigrationStep A step of a schema migration
+type MigrationStep struct {
+	Name string
+	// Idempotent whether the step can safely run again after being partially applied (CREATE TABLE IF NOT EXISTS).
+	// A step that is not idempotent is only retried on errors marked with NotApplied.
+	Idempotent bool
+	Apply      func(ctx context.Context) error
+}
+
+// MigrationError reports the step that stopped a migration run
+type MigrationError struct {
+	Step int    // index of the step, -1 when the lock could not be acquired
+	Name string // name of the step
+	Err  error
+}
+
+func (e *MigrationError) Error() string {
+	if e.Step < 0 {
+		return "retry: migration lock not acquired: " + e.Err.Error()
+	}
+	return "retry: migration step " + strconv.Itoa(e.Step) + " (" + e.Name + ") failed: " + e.Err.Error()
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// Migration Runs schema migrations (DDL) with retries, never retrying a step after it may have been partially applied
+type Migration struct {
+	// Retry the retry policy of the lock acquisition and of each step
+	Retry *Retry
+	// Lock optional, acquires the migration lock held during the whole run. Acquisition errors are retried regardless
+	// of the RetryIf of the policy, unless marked with Unrecoverable.
+	Lock  func(ctx context.Context) (release func(), err error)
+	Steps []MigrationStep
+}
+
+// Run acquires the lock and applies the steps in order, stopping at the first step that gives up. Returns a
+// *MigrationError identifying that step.
+func (m *Migration) Run(ctx context.Context) error {
+	if m.Lock != nil {
+		var release func()
+		lockRetry := m.Retry.clone()
+		lockRetry.retryIf = nil
+		err := lockRetry.Execute(ctx, func(ctx context.Context, attempt int) error {
+			var err error
+			release, err = m.Lock(ctx)
+			return err
+		})
+		if err != nil {
+			return &MigrationError{Step: -1, Name: "lock", Err: err}
+		}
+		if release != nil {
+			defer release()
+		}
+	}
+
+	for i, step := range m.Steps {
+		err := m.Retry.Execute(ctx, func(ctx context.Context, attempt int) error {
+			err := step.Apply(ctx)
+			if err != nil && !step.Idempotent && !errors.Is(err, ErrNotApplied) {
+				// the statement may have been partially applied
+				return Unrecoverable(err)
+			}
+			return err
+		})
+		if err != nil {
+			return &MigrationError{Step: i, Name: step.Name, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Migration(t *testing.T) {
+
+	var applied []string
+	lockAttempts, released := 0, false
+	errTimeout := errors.New("lock timeout")
+
+	m := &Migration{
+		Retry: NewWithOptions(WithRetries(3), WithFixedBackOff(time.Millisecond)),
+		Lock: func(ctx context.Context) (func(), error) {
+			lockAttempts++
+			if lockAttempts < 2 {
+				return nil, errTimeout
+			}
+			return func() { released = true }, nil
+		},
+		Steps: []MigrationStep{
+			{Name: "create table", Idempotent: true, Apply: func(ctx context.Context) error {
+				applied = append(applied, "create")
+				if len(applied) < 2 {
+					return errors.New("connection reset")
+				}
+				return nil
+			}},
+			{Name: "add column", Apply: func(ctx context.Context) error {
+				applied = append(applied, "alter")
+				if len(applied) < 4 {
+					return NotApplied(errTimeout)
+				}
+				return nil
+			}},
+		},
+	}
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if lockAttempts != 2 || !released {
+		t.Fatalf("Lock not expected, attempts: %d, released: %v", lockAttempts, released)
+	}
+	if len(applied) != 4 {
+		t.Fatalf("Steps not expected, got %v", applied)
+	}
+}
+
+func Test_MigrationPartiallyApplied(t *testing.T) {
+
+	errPartial := errors.New("statement timeout")
+	calls := 0
+
+	m := &Migration{
+		Retry: NewWithOptions(WithRetries(3), WithFixedBackOff(time.Millisecond)),
+		Steps: []MigrationStep{
+			{Name: "create table", Idempotent: true, Apply: func(ctx context.Context) error {
+				return nil
+			}},
+			{Name: "backfill", Apply: func(ctx context.Context) error {
+				calls++
+				return errPartial
+			}},
+			{Name: "drop column", Apply: func(ctx context.Context) error {
+				t.Fatalf("Step not expected")
+				return nil
+			}},
+		},
+	}
+
+	err := m.Run(context.Background())
+	var migrationErr *MigrationError
+	if !errors.As(err, &migrationErr) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if migrationErr.Step != 1 || migrationErr.Name != "backfill" || !errors.Is(err, errPartial) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Calls not equal, want: %d, got %d", 1, calls)
+	}
+}
+
+func Test_MigrationLockIgnoresRetryIf(t *testing.T) {
+
+	errLocked := errors.New("lock not available")
+	lockAttempts := 0
+
+	m := &Migration{
+		Retry: NewWithOptions(
+			WithRetries(3),
+			WithFixedBackOff(time.Millisecond),
+			WithRetryIf(func(err error) bool { return !errors.Is(err, errLocked) }),
+		),
+		Lock: func(ctx context.Context) (func(), error) {
+			lockAttempts++
+			if lockAttempts < 3 {
+				return nil, errLocked
+			}
+			return nil, nil
+		},
+	}
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if lockAttempts != 3 {
+		t.Fatalf("Lock attempts not equal, want: %d, got %d", 3, lockAttempts)
+	}
+
+	lockAttempts = 0
+	m.Lock = func(ctx context.Context) (func(), error) {
+		lockAttempts++
+		return nil, Unrecoverable(errLocked)
+	}
+	if err := m.Run(context.Background()); !errors.Is(err, errLocked) || lockAttempts != 1 {
+		t.Fatalf("Unrecoverable lock error not expected, attempts: %d, got %v", lockAttempts, err)
+	}
+}
@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrNegativeCache = errors.New("retry: failing fast, key recently gave up")
+
+// NegativeCache Remembers the keys whose executions gave up for a TTL, so subsequent executions with the same key fail
+// fast instead of repeating a doomed retry sequence (e.g. hot keys). Safe for concurrent use.
+type NegativeCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]negativeEntry
+	now     func() time.Time
+}
+
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+// NewNegativeCache initialize new NegativeCache
+func NewNegativeCache(ttl time.Duration) *NegativeCache {
+	return &NegativeCache{
+		ttl:     ttl,
+		entries: map[string]negativeEntry{},
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached failure of key, or nil
+func (c *NegativeCache) Get(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return entry.err
+}
+
+// Put remembers the failure of key for the TTL
+func (c *NegativeCache) Put(key string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = negativeEntry{err: err, expires: now.Add(c.ttl)}
+}
+
+// Forget removes the cached failure of key
+func (c *NegativeCache) Forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// SetNegativeCache Set the cache of failures used by ExecuteKey
+//
+// Deprecated: use NewWithOptions with WithNegativeCache.
+func (r *Retry) SetNegativeCache(cache *NegativeCache) {
+	r.apply(WithNegativeCache(cache))
+}
+
+// ExecuteKey same as Execute, for an operation identified by key. When a NegativeCache is set, fails fast with an
+// error matching ErrNegativeCache (and the cached error) if a previous execution of key gave up within the TTL.
+// Cancellation of the context is not cached.
+func (r *Retry) ExecuteKey(ctx context.Context, key string, callback func(ctx context.Context, attempt int) error) error {
+	if r.negative == nil {
+		return r.Execute(ctx, callback)
+	}
+
+	if cached := r.negative.Get(key); cached != nil {
+		return fmt.Errorf("%w: %w", ErrNegativeCache, cached)
+	}
+
+	err := r.Execute(ctx, callback)
+	if err != nil && ctx.Err() == nil {
+		r.negative.Put(key, err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_NegativeCache(t *testing.T) {
+
+	now := time.Now()
+	cache := NewNegativeCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	callback := func(ctx context.Context, attempt int) error {
+		calls++
+		return customErr
+	}
+
+	retries := New(1, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetNegativeCache(cache)
+
+	if err := retries.ExecuteKey(context.Background(), "user:42", callback); !errors.Is(err, customErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", customErr, err)
+	}
+
+	err := retries.ExecuteKey(context.Background(), "user:42", callback)
+	if !errors.Is(err, ErrNegativeCache) || !errors.Is(err, customErr) {
+		t.Fatalf("ErrNegativeCache expected, got %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 2, calls)
+	}
+
+	// other keys are not affected
+	_ = retries.ExecuteKey(context.Background(), "user:43", callback)
+	if calls != 4 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 4, calls)
+	}
+
+	// expired
+	now = now.Add(2 * time.Minute)
+	_ = retries.ExecuteKey(context.Background(), "user:42", callback)
+	if calls != 6 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 6, calls)
+	}
+}
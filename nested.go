@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Retry levels reported by NestedOnError
+const (
+	LevelInner = "inner"
+	LevelOuter = "outer"
+)
+
+// NestedOnError receives the merged error events of both levels of a Nested execution
+type NestedOnError func(ctx context.Context, err error, level string, outerAttempt int, innerAttempt int, willRetry bool, nextRetry time.Duration)
+
+// Nested composes two policies: Inner performs quick transport-level retries inside each logical attempt of Outer,
+// a slower policy. The budgets are combined: each logical attempt, inner retries included, is bounded by what remains
+// of the max elapsed time of Outer.
+type Nested struct {
+	Outer   *Retry
+	Inner   *Retry
+	OnError NestedOnError // optional, replaces the OnError of both policies with a single event stream
+}
+
+// Execute runs the callback until it succeeds or both policies give up
+func (n *Nested) Execute(ctx context.Context, callback func(ctx context.Context, outerAttempt int, innerAttempt int) error) error {
+	outer := n.Outer.clone()
+	inner := n.Inner.clone()
+
+	outerAttempt := 0
+	if n.OnError != nil {
+		outer.onError = func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			n.OnError(ctx, err, LevelOuter, attempt, 0, willRetry, nextRetry)
+		}
+		inner.onError = func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			if willRetry {
+				// inner give-up is reported by the outer level
+				n.OnError(ctx, err, LevelInner, outerAttempt, attempt, willRetry, nextRetry)
+			}
+		}
+	}
+
+	started := outer.now()
+	return outer.Execute(ctx, func(ctx context.Context, attempt int) error {
+		outerAttempt = attempt
+
+		attemptInner := inner
+		if outer.maxElapsed > 0 {
+			// the inner retries share the remaining budget of the outer level
+			remaining := outer.maxElapsed - outer.since(started)
+			if remaining <= 0 {
+				return ErrMaxElapsedTime
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, remaining)
+			defer cancel()
+			if inner.maxElapsed <= 0 || remaining < inner.maxElapsed {
+				attemptInner = inner.clone()
+				attemptInner.maxElapsed = remaining
+			}
+		}
+
+		return attemptInner.Execute(ctx, func(ctx context.Context, innerAttempt int) error {
+			return callback(ctx, outerAttempt, innerAttempt)
+		})
+	})
+}
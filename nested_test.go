@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Nested(t *testing.T) {
+
+	var events []string
+
+	outer := New(2, nil)
+	outer.SetFixedBackOff(2)
+
+	inner := New(1, nil)
+	inner.SetFixedBackOff(1)
+
+	nested := &Nested{
+		Outer: outer,
+		Inner: inner,
+		OnError: func(ctx context.Context, err error, level string, outerAttempt int, innerAttempt int, willRetry bool, nextRetry time.Duration) {
+			events = append(events, level)
+		},
+	}
+
+	calls := 0
+	err := nested.Execute(context.Background(), func(ctx context.Context, outerAttempt int, innerAttempt int) error {
+		calls++
+		if outerAttempt < 3 {
+			return customErr
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	// outer 1: inner 1, 2 | outer 2: inner 1, 2 | outer 3: inner 1
+	if calls != 5 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 5, calls)
+	}
+
+	want := []string{LevelInner, LevelOuter, LevelInner, LevelOuter}
+	if len(events) != len(want) {
+		t.Fatalf("Events not equal, want: %v, got %v", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("Events not equal, want: %v, got %v", want, events)
+		}
+	}
+}
+
+func Test_NestedCombinedBudget(t *testing.T) {
+
+	outer := NewWithOptions(WithRetries(-1), WithFixedBackOff(time.Millisecond), WithMaxElapsedTime(60*time.Millisecond))
+	inner := NewWithOptions(WithRetries(-1), WithFixedBackOff(10*time.Millisecond))
+
+	nested := &Nested{Outer: outer, Inner: inner}
+
+	start := time.Now()
+	err := nested.Execute(context.Background(), func(ctx context.Context, outerAttempt int, innerAttempt int) error {
+		return customErr
+	})
+
+	if err == nil {
+		t.Fatalf("Error expected")
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("Outer budget overrun, elapsed: %s", elapsed)
+	}
+}
@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+type memoKey struct{}
+
+// onceKey identifies a cached value by its key and type, so Once calls with the same key and different types don't
+// collide
+type onceKey struct {
+	key string
+	typ any // typed nil pointer of the value
+}
+
+type memo struct {
+	mu      sync.Mutex
+	values  map[onceKey]any
+	claimed map[any]bool
+}
+
+func withMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoKey{}, &memo{values: map[onceKey]any{}, claimed: map[any]bool{}})
+}
+
+// claimOnce reports whether the key is claimed for the first time in the Execute of the context, safe for concurrent
+// attempts. Outside an Execute, always true.
+func claimOnce(ctx context.Context, key any) bool {
+	m, ok := ctx.Value(memoKey{}).(*memo)
+	if !ok {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.claimed[key] {
+		return false
+	}
+	m.claimed[key] = true
+	return true
+}
+
+// Once runs an idempotent sub-step inside a retried callback, caching its successful result for the duration of
+// the Execute, so later attempts don't redo sub-steps that already succeeded. Errors are not cached. Values are cached
+// by key and type. Outside an Execute, fn is always invoked.
+func Once[T any](ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	m, ok := ctx.Value(memoKey{}).(*memo)
+	if !ok {
+		return fn(ctx)
+	}
+
+	k := onceKey{key: key, typ: (*T)(nil)}
+	m.mu.Lock()
+	if v, found := m.values[k]; found {
+		m.mu.Unlock()
+		// a nil interface value is cached as nil
+		value, _ := v.(T)
+		return value, nil
+	}
+	m.mu.Unlock()
+
+	v, err := fn(ctx)
+	if err != nil {
+		return v, err
+	}
+
+	m.mu.Lock()
+	m.values[k] = v
+	m.mu.Unlock()
+	return v, nil
+}
@@ -0,0 +1,75 @@
+package retry
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Once(t *testing.T) {
+
+	countUpload := 0
+	countFetch := 0
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		url, err := Once(ctx, "upload", func(ctx context.Context) (string, error) {
+			countUpload++
+			return "s3://bucket/file", nil
+		})
+		if err != nil {
+			return err
+		}
+		if url != "s3://bucket/file" {
+			t.Fatalf("Once value not equal, want: %s, got %s", "s3://bucket/file", url)
+		}
+
+		_, err = Once(ctx, "fetch", func(ctx context.Context) (int, error) {
+			countFetch++
+			if attempt < 3 {
+				return 0, customErr
+			}
+			return 1, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return executeFn(ctx, attempt)
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if countUpload != 1 {
+		t.Fatalf("Count upload not equal, want: %d, got %d", 1, countUpload)
+	}
+
+	// failed in attempts 1 and 2, cached from attempt 3
+	if countFetch != 3 {
+		t.Fatalf("Count fetch not equal, want: %d, got %d", 3, countFetch)
+	}
+}
+
+func Test_OnceSameKeyDifferentTypes(t *testing.T) {
+
+	retries := New(0, nil)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		n, _ := Once(ctx, "value", func(ctx context.Context) (int, error) { return 1, nil })
+		s, _ := Once(ctx, "value", func(ctx context.Context) (string, error) { return "one", nil })
+		var e error
+		e, _ = Once(ctx, "nil", func(ctx context.Context) (error, error) { return nil, nil })
+		e, _ = Once(ctx, "nil", func(ctx context.Context) (error, error) { return customErr, nil })
+		if n != 1 || s != "one" || e != nil {
+			t.Fatalf("Values not expected, got %v, %q, %v", n, s, e)
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Option configures a Retry created with NewWithOptions
+type Option func(r *Retry)
+
+// NewWithOptions initialize new frozen Retry, safe for concurrent Execute calls (see Freeze). Without options, it
+// makes 1 attempt with no retries and a fixed backoff of 1s.
+//
+//	retries := retry.NewWithOptions(
+//	    retry.WithRetries(5),
+//	    retry.WithExponentialBackoff(500*time.Millisecond, 5*time.Second, 2),
+//	    retry.WithBackoffJitter(retry.FullJitter, nil),
+//	    retry.WithOnError(logErrors),
+//	)
+func NewWithOptions(opts ...Option) *Retry {
+	r := New(0, nil)
+	r.apply(opts...)
+	r.Freeze()
+	return r
+}
+
+// WithRetries the number of retries that are to be attempted before giving up. To try forever, use -1.
+func WithRetries(retries int) Option {
+	return func(r *Retry) {
+		r.retries = retries
+		r.unlimited = retries < 0
+	}
+}
+
+// WithOnError the hook invoked after each failed attempt
+func WithOnError(onError OnError) Option {
+	return func(r *Retry) {
+		r.onError = onError
+	}
+}
+
+// WithRetryIf the predicate that classifies errors as retryable. See SetRetryIf.
+func WithRetryIf(retryIf func(err error) bool) Option {
+	return func(r *Retry) {
+		r.retryIf = retryIf
+	}
+}
+
+// WithBackoff the backoff strategy
+func WithBackoff(strategy BackoffStrategy) Option {
+	return func(r *Retry) {
+		r.Backoff = strategy
+	}
+}
+
+// WithFixedBackOff pauses for a fixed period between attempts. See FixedBackOffStrategy.
+func WithFixedBackOff(period time.Duration) Option {
+	return WithBackoff(NewFixedBackOff(period))
+}
+
+// WithExponentialBackoff See ExponentialBackoffStrategy.
+func WithExponentialBackoff(initTime time.Duration, maxTime time.Duration, factor float64) Option {
+	return WithBackoff(NewExponentialBackoff(initTime, maxTime, factor))
+}
+
+// WithLinearBackoff See LinearBackoffStrategy.
+func WithLinearBackoff(initTime time.Duration, increment time.Duration, maxTime time.Duration) Option {
+	return WithBackoff(NewLinearBackoff(initTime, increment, maxTime))
+}
+
+// WithFibonacciBackoff See FibonacciBackoffStrategy.
+func WithFibonacciBackoff(initTime time.Duration, maxTime time.Duration) Option {
+	return WithBackoff(NewFibonacciBackoff(initTime, maxTime))
+}
+
+// WithBackoffJitter randomizes the backoff strategy configured by the previous options. See WithJitter.
+func WithBackoffJitter(mode JitterMode, random *rand.Rand) Option {
+	return func(r *Retry) {
+		r.Backoff = WithJitter(r.Backoff, mode, random)
+	}
+}
+
+// WithHashedJitter spreads the backoff strategy configured by the previous options by a stable offset derived from
+// the job key. See HashedJitterBackoffStrategy.
+func WithHashedJitter(key string, spread time.Duration) Option {
+	return func(r *Retry) {
+		r.Backoff = NewHashedJitterBackoffStrategy(r.Backoff, key, spread)
+	}
+}
+
+// WithAttemptTimeout the maximum duration of each attempt. See SetAttemptTimeout.
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(r *Retry) {
+		r.timeout = timeout
+	}
+}
+
+// WithMaxElapsedTime the total budget of an execution. See SetMaxElapsedTime.
+func WithMaxElapsedTime(maxElapsed time.Duration) Option {
+	return func(r *Retry) {
+		r.maxElapsed = maxElapsed
+	}
+}
+
+// WithBeforeRetry the hook invoked before each retry attempt. See BeforeRetry.
+func WithBeforeRetry(beforeRetry BeforeRetry) Option {
+	return func(r *Retry) {
+		r.beforeRetry = beforeRetry
+	}
+}
+
+// WithAttemptContext the function used to derive the context of each attempt. See AttemptContext.
+func WithAttemptContext(attemptCtx AttemptContext) Option {
+	return func(r *Retry) {
+		r.attemptCtx = attemptCtx
+	}
+}
+
+// WithStaleAfter guards against retries based on stale inputs. See SetStaleAfter.
+func WithStaleAfter(staleAfter time.Duration, refresh func(ctx context.Context) error) Option {
+	return func(r *Retry) {
+		r.staleAfter = staleAfter
+		r.refresh = refresh
+	}
+}
+
+// WithAttemptDeadlineFromBackoff See SetAttemptDeadlineFromBackoff.
+func WithAttemptDeadlineFromBackoff(enabled bool) Option {
+	return func(r *Retry) {
+		r.headroom = enabled
+	}
+}
+
+// WithCancellationPolicy when cancellation of the context is observed. See CancellationPolicy.
+func WithCancellationPolicy(policy CancellationPolicy) Option {
+	return func(r *Retry) {
+		r.cancel = policy
+	}
+}
+
+// WithThrottle the client-side adaptive throttle. See SetThrottle.
+func WithThrottle(throttle *AdaptiveThrottle) Option {
+	return func(r *Retry) {
+		r.throttle = throttle
+	}
+}
+
+// WithWaiter the mechanism used to wait for backoff delays. See Waiter.
+func WithWaiter(waiter Waiter) Option {
+	return func(r *Retry) {
+		r.waiter = waiter
+	}
+}
+
+// WithNegativeCache the cache of failures used by ExecuteKey. See NegativeCache.
+func WithNegativeCache(cache *NegativeCache) Option {
+	return func(r *Retry) {
+		r.negative = cache
+	}
+}
+
+// WithOnAttempt the hook invoked before each attempt
+func WithOnAttempt(onAttempt OnAttempt) Option {
+	return func(r *Retry) {
+		r.onAttempt = onAttempt
+	}
+}
+
+// WithOnSuccess the hook invoked when the callback succeeds
+func WithOnSuccess(onSuccess OnSuccess) Option {
+	return func(r *Retry) {
+		r.onSuccess = onSuccess
+	}
+}
+
+// WithOnGiveUp the hook invoked when the execution gives up
+func WithOnGiveUp(onGiveUp OnGiveUp) Option {
+	return func(r *Retry) {
+		r.onGiveUp = onGiveUp
+	}
+}
+
+// WithTracer the tracer used to create a span per attempt. See Tracer.
+func WithTracer(tracer Tracer) Option {
+	return func(r *Retry) {
+		r.tracer = tracer
+	}
+}
+
+// WithHintExtractors the extractors of retry hints from errors. See SetHintExtractors.
+func WithHintExtractors(extractors ...HintExtractor) Option {
+	return func(r *Retry) {
+		r.hints = extractors
+	}
+}
+
+// WithHedgeDelay the delay before ExecuteHedged launches a parallel attempt
+func WithHedgeDelay(delay time.Duration) Option {
+	return func(r *Retry) {
+		r.hedgeDelay = delay
+	}
+}
+
+// WithMaxHedges the maximum number of parallel attempts launched by ExecuteHedged in addition to the first one
+func WithMaxHedges(maxHedges int) Option {
+	return func(r *Retry) {
+		r.maxHedges = maxHedges
+	}
+}
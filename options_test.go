@@ -0,0 +1,124 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_NewWithOptions(t *testing.T) {
+
+	var failures int32
+	retries := NewWithOptions(
+		WithRetries(3),
+		WithExponentialBackoff(time.Millisecond, 4*time.Millisecond, 2),
+		WithOnError(func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			atomic.AddInt32(&failures, 1)
+		}),
+	)
+
+	if err := retries.Execute(context.Background(), executeFn); err != nil {
+		t.Fatalf("Error not expected")
+	}
+	if failures != 3 {
+		t.Fatalf("Failures not equal, want: %d, got %d", 3, failures)
+	}
+}
+
+func Test_NewWithOptionsDefaults(t *testing.T) {
+
+	retries := NewWithOptions()
+
+	attempts := 0
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		attempts++
+		return errors.New("fail")
+	})
+	if err == nil {
+		t.Fatalf("Error expected")
+	}
+	if attempts != 1 {
+		t.Fatalf("Attempts not equal, want: %d, got %d", 1, attempts)
+	}
+	if d := retries.backoffStrategy().NextDelay(1, nil); d != time.Second {
+		t.Fatalf("Delay not equal, want: %v, got %v", time.Second, d)
+	}
+}
+
+func Test_NewWithOptionsRetryIf(t *testing.T) {
+
+	errFatal := errors.New("fatal")
+	retries := NewWithOptions(
+		WithRetries(5),
+		WithFixedBackOff(time.Millisecond),
+		WithRetryIf(func(err error) bool {
+			return !errors.Is(err, errFatal)
+		}),
+	)
+
+	attempts := 0
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		attempts++
+		return errFatal
+	})
+	if !errors.Is(err, errFatal) {
+		t.Fatalf("Error not equal, want: %v, got %v", errFatal, err)
+	}
+	if attempts != 1 {
+		t.Fatalf("Attempts not equal, want: %d, got %d", 1, attempts)
+	}
+}
+
+func Test_NewWithOptionsImmutable(t *testing.T) {
+
+	retries := NewWithOptions(WithFixedBackOff(time.Millisecond))
+
+	// writes to the exported field are ignored
+	retries.Backoff = NewFixedBackOff(time.Hour)
+	if d := retries.backoffStrategy().NextDelay(1, nil); d != time.Millisecond {
+		t.Fatalf("Delay not equal, want: %v, got %v", time.Millisecond, d)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Panic expected")
+		}
+	}()
+	retries.SetNumberOfRetries(3)
+}
+
+func Test_NewWithOptionsConcurrent(t *testing.T) {
+
+	retries := NewWithOptions(
+		WithRetries(3),
+		WithBackoffJitter(FullJitter, nil),
+		WithWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+			return nil
+		})),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+				if attempt < 3 {
+					return errors.New("fail")
+				}
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Error not expected, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := retries.Stats(); stats.Executions != 20 || stats.Attempts != 60 {
+		t.Fatalf("Stats not expected, got %+v", stats)
+	}
+}
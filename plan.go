@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// maxPlannedAttempts bounds the planning of unlimited retries
+const maxPlannedAttempts = 10000
+
+// Plan The attempts planned by an execution to fit in the deadline of its context
+type Plan struct {
+	Deadline time.Time       // deadline of the context
+	Attempts int             // attempts that fit before the deadline (first attempt + retries), at least 1
+	Delays   []time.Duration // backoff delay planned after each failed attempt, except the last one
+}
+
+// OnPlan is invoked at the start of each execution whose context has a deadline, with the planned attempts
+type OnPlan func(ctx context.Context, plan Plan)
+
+// WithDeadlinePlanning makes each execution whose context has a deadline compute how many attempts realistically fit
+// in the remaining time, given the backoff strategy and the expected latency of an attempt, and give up after them
+// instead of starting attempts doomed to be canceled. The number of retries remains an upper bound. Executions without
+// a deadline, and ExecuteHedged, are not planned.
+// expectedLatency - expected duration of an attempt
+// onPlan - optional, reports the plan for observability
+func WithDeadlinePlanning(expectedLatency time.Duration, onPlan OnPlan) Option {
+	return func(r *Retry) {
+		r.planLatency = expectedLatency
+		r.onPlan = onPlan
+	}
+}
+
+// plan returns the number of retries of an execution and whether they are unlimited
+func (r *Retry) plan(ctx context.Context, started time.Time) (int, bool) {
+	if r.planLatency <= 0 {
+		return r.retries, r.unlimited
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return r.retries, r.unlimited
+	}
+
+	strategy := r.backoffStrategy()
+	plan := Plan{Deadline: deadline, Attempts: 1}
+	remaining := deadline.Sub(started) - r.planLatency
+	for plan.Attempts < maxPlannedAttempts && (r.unlimited || plan.Attempts <= r.retries) {
+		delay := strategy.NextDelay(plan.Attempts, nil)
+		if remaining < delay+r.planLatency {
+			break
+		}
+		remaining -= delay + r.planLatency
+		plan.Delays = append(plan.Delays, delay)
+		plan.Attempts++
+	}
+
+	if r.onPlan != nil {
+		r.onPlan(ctx, plan)
+	}
+	return plan.Attempts - 1, false
+}
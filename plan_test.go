@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_DeadlinePlanning(t *testing.T) {
+
+	var plan Plan
+	retries := NewWithOptions(
+		WithRetries(-1),
+		WithFixedBackOff(10*time.Millisecond),
+		WithDeadlinePlanning(200*time.Millisecond, func(ctx context.Context, p Plan) {
+			plan = p
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// 200ms + 3 * (10ms + 200ms) fit in 1s
+	attempts := 0
+	err := retries.Execute(ctx, func(ctx context.Context, attempt int) error {
+		attempts++
+		return errors.New("fail")
+	})
+	var retryErr *Error
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if attempts != 4 || plan.Attempts != 4 {
+		t.Fatalf("Attempts not equal, want: %d, got %d (planned %d)", 4, attempts, plan.Attempts)
+	}
+	if len(plan.Delays) != 3 || plan.Delays[0] != 10*time.Millisecond {
+		t.Fatalf("Delays not expected, got %v", plan.Delays)
+	}
+}
+
+func Test_DeadlinePlanningBounds(t *testing.T) {
+
+	plans := 0
+	retries := NewWithOptions(
+		WithRetries(2),
+		WithFixedBackOff(time.Millisecond),
+		WithDeadlinePlanning(time.Millisecond, func(ctx context.Context, p Plan) {
+			plans++
+			if p.Attempts != 3 {
+				t.Fatalf("Attempts not equal, want: %d, got %d", 3, p.Attempts)
+			}
+		}),
+	)
+
+	// the number of retries remains an upper bound
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	fail := func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	}
+	_ = retries.Execute(ctx, fail)
+
+	// executions without a deadline are not planned
+	_ = retries.Execute(context.Background(), fail)
+	if plans != 1 {
+		t.Fatalf("Plans not equal, want: %d, got %d", 1, plans)
+	}
+
+	// the first attempt is always made
+	short, cancelShort := context.WithTimeout(context.Background(), time.Microsecond)
+	defer cancelShort()
+	retries = NewWithOptions(WithRetries(5), WithDeadlinePlanning(time.Second, nil))
+	if p, _ := retries.plan(short, time.Now()); p != 0 {
+		t.Fatalf("Retries not equal, want: %d, got %d", 0, p)
+	}
+}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+var ErrUnknownPolicy = errors.New("retry: unknown policy")
+
+// Policies Resolves the Retry of each endpoint of a generated client (OpenAPI, gRPC) from a declarative mapping of
+// method patterns to policy names, so retry behavior is declared per endpoint in one reviewed file. The mapping is a
+// JSON document whose rules are evaluated in order, the first matching pattern wins:
+//
+//	{
+//	  "rules": [
+//	    {"pattern": "/orders.v1.Orders/Get*", "policy": "idempotent"},
+//	    {"pattern": "/orders.v1.Orders/*", "policy": "none"}
+//	  ]
+//	}
+//
+// Patterns follow the syntax of path.Match.
+type Policies struct {
+	rules    []policyRule
+	policies map[string]*Retry
+}
+
+type policyRule struct {
+	Pattern string `json:"pattern"`
+	Policy  string `json:"policy"`
+}
+
+// LoadPolicies reads the mapping, resolving the policy names from the given Retry by name. Fails on malformed
+// patterns, and with ErrUnknownPolicy when a rule references a policy not given.
+func LoadPolicies(mapping io.Reader, policies map[string]*Retry) (*Policies, error) {
+	var doc struct {
+		Rules []policyRule `json:"rules"`
+	}
+	decoder := json.NewDecoder(mapping)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("retry: invalid policy mapping: %w", err)
+	}
+
+	for i, rule := range doc.Rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("retry: invalid pattern %q in rule %d: %w", rule.Pattern, i, err)
+		}
+		if _, ok := policies[rule.Policy]; !ok {
+			return nil, fmt.Errorf("%w %q in rule %d", ErrUnknownPolicy, rule.Policy, i)
+		}
+	}
+	return &Policies{rules: doc.Rules, policies: policies}, nil
+}
+
+// Match returns the Retry of the first rule matching the method, false if none matches
+func (p *Policies) Match(method string) (*Retry, bool) {
+	for _, rule := range p.rules {
+		if ok, _ := path.Match(rule.Pattern, method); ok {
+			return p.policies[rule.Policy], true
+		}
+	}
+	return nil, false
+}
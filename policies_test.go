@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_Policies(t *testing.T) {
+
+	idempotent := NewWithOptions(WithRetries(5))
+	none := NewWithOptions()
+
+	policies, err := LoadPolicies(strings.NewReader(`{
+		"rules": [
+			{"pattern": "/orders.v1.Orders/Get*", "policy": "idempotent"},
+			{"pattern": "/orders.v1.Orders/*", "policy": "none"}
+		]
+	}`), map[string]*Retry{"idempotent": idempotent, "none": none})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+
+	if r, ok := policies.Match("/orders.v1.Orders/GetOrder"); !ok || r != idempotent {
+		t.Fatalf("Policy not expected for GetOrder")
+	}
+	if r, ok := policies.Match("/orders.v1.Orders/CreateOrder"); !ok || r != none {
+		t.Fatalf("Policy not expected for CreateOrder")
+	}
+	if _, ok := policies.Match("/users.v1.Users/GetUser"); ok {
+		t.Fatalf("Policy not expected for GetUser")
+	}
+}
+
+func Test_PoliciesInvalid(t *testing.T) {
+
+	policies := map[string]*Retry{"default": New(3, nil)}
+
+	_, err := LoadPolicies(strings.NewReader(`{"rules": [{"pattern": "*", "policy": "missing"}]}`), policies)
+	if !errors.Is(err, ErrUnknownPolicy) {
+		t.Fatalf("Error not equal, want: %v, got %v", ErrUnknownPolicy, err)
+	}
+
+	_, err = LoadPolicies(strings.NewReader(`{"rules": [{"pattern": "[", "policy": "default"}]}`), policies)
+	if err == nil {
+		t.Fatalf("Error expected for malformed pattern")
+	}
+
+	_, err = LoadPolicies(strings.NewReader(`{"rules": [{"method": "*", "policy": "default"}]}`), policies)
+	if err == nil {
+		t.Fatalf("Error expected for unknown field")
+	}
+}
@@ -0,0 +1,47 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Poll Keep polling until the condition is met. While the condition is not met, waits the poll interval before the
+// next check; errors follow the retry strategy (number of retries and backoff) like Execute.
+//
+// The polling between two errors runs as a single attempt, so the per-attempt deadlines (attempt timeout, adaptive
+// timeout, deadline from backoff) bound each check of the condition instead, and the watchdog is not used.
+func (r *Retry) Poll(ctx context.Context, interval time.Duration, until func(ctx context.Context) (done bool, err error)) error {
+	timeout := r.attemptTimeout()
+
+	poller := r.clone()
+	poller.timeout = 0
+	poller.adaptive = nil
+	poller.headroom = false
+	poller.stuckAfter = 0
+
+	return poller.Execute(ctx, func(ctx context.Context, attempt int) error {
+		for {
+			done, err := check(ctx, timeout, until)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+
+			if err = r.wait(ctx, interval); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// check checks the condition, bounded by the given timeout when not negative
+func check(ctx context.Context, timeout time.Duration, until func(ctx context.Context) (done bool, err error)) (bool, error) {
+	if timeout < 0 {
+		return until(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return until(ctx)
+}
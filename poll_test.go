@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Poll(t *testing.T) {
+
+	countError := 0
+	checks := 0
+
+	retries := New(3, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		countError++
+	})
+	retries.SetFixedBackOff(1)
+
+	err := retries.Poll(context.Background(), time.Millisecond, func(ctx context.Context) (bool, error) {
+		checks++
+		switch checks {
+		case 2, 4:
+			return false, customErr
+		case 6:
+			return true, nil
+		}
+		return false, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if checks != 6 {
+		t.Fatalf("Count checks not equal, want: %d, got %d", 6, checks)
+	}
+
+	if countError != 2 {
+		t.Fatalf("Count error not equal, want: %d, got %d", 2, countError)
+	}
+}
+
+func Test_PollCancelContext(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := New(3, nil).Poll(ctx, time.Millisecond, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Error not equal, want: %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func Test_PollAttemptTimeout(t *testing.T) {
+
+	retries := NewWithOptions(
+		WithRetries(0),
+		WithAttemptTimeout(20*time.Millisecond),
+		WithAttemptDeadlineFromBackoff(true),
+	)
+
+	checks := 0
+	err := retries.Poll(context.Background(), 5*time.Millisecond, func(ctx context.Context) (bool, error) {
+		checks++
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > 20*time.Millisecond {
+			t.Fatalf("Check not bounded by the attempt timeout")
+		}
+		// polling for longer than the attempt timeout
+		return checks == 10, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	if checks != 10 {
+		t.Fatalf("Count checks not equal, want: %d, got %d", 10, checks)
+	}
+}
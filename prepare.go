@@ -0,0 +1,22 @@
+package retry
+
+import "context"
+
+// Prepare builds fresh input for an attempt (new request ID, re-read file, re-signed URL), given the error of the
+// previous attempt (nil on the first one)
+type Prepare[In any] func(ctx context.Context, attempt int, prevErr error) (In, error)
+
+// ExecuteWith same as Execute, with the input of each attempt rebuilt by prepare, formalizing the pattern of rebuilding
+// inputs per attempt that closures often get wrong. An error returned by prepare fails the attempt like an error of
+// the callback, mark it with Unrecoverable to abort.
+func ExecuteWith[In any](ctx context.Context, r *Retry, prepare Prepare[In], callback func(ctx context.Context, attempt int, in In) error) error {
+	var prevErr error
+	return r.Execute(ctx, func(ctx context.Context, attempt int) error {
+		in, err := prepare(ctx, attempt, prevErr)
+		if err == nil {
+			err = callback(ctx, attempt, in)
+		}
+		prevErr = err
+		return err
+	})
+}
@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteWith(t *testing.T) {
+
+	errExpired := errors.New("signature expired")
+	retries := NewWithOptions(WithRetries(3), WithFixedBackOff(time.Millisecond))
+
+	var prevErrs []error
+	var urls []string
+	err := ExecuteWith(context.Background(), retries,
+		func(ctx context.Context, attempt int, prevErr error) (string, error) {
+			prevErrs = append(prevErrs, prevErr)
+			return "https://bucket/object?sig=" + strconv.Itoa(attempt), nil
+		},
+		func(ctx context.Context, attempt int, url string) error {
+			urls = append(urls, url)
+			if attempt < 3 {
+				return errExpired
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if len(urls) != 3 || urls[2] != "https://bucket/object?sig=3" {
+		t.Fatalf("Inputs not expected, got %v", urls)
+	}
+	if prevErrs[0] != nil || prevErrs[1] != errExpired || prevErrs[2] != errExpired {
+		t.Fatalf("Previous errors not expected, got %v", prevErrs)
+	}
+}
+
+func Test_ExecuteWithPrepareError(t *testing.T) {
+
+	errMissing := errors.New("file missing")
+	retries := NewWithOptions(WithRetries(3), WithFixedBackOff(time.Millisecond))
+
+	prepares := 0
+	err := ExecuteWith(context.Background(), retries,
+		func(ctx context.Context, attempt int, prevErr error) ([]byte, error) {
+			prepares++
+			return nil, Unrecoverable(errMissing)
+		},
+		func(ctx context.Context, attempt int, in []byte) error {
+			t.Fatalf("Callback not expected")
+			return nil
+		},
+	)
+	if !errors.Is(err, errMissing) {
+		t.Fatalf("Error not equal, want: %v, got %v", errMissing, err)
+	}
+	if prepares != 1 {
+		t.Fatalf("Prepares not equal, want: %d, got %d", 1, prepares)
+	}
+}
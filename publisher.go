@@ -0,0 +1,73 @@
+package retry
+
+import "time"
+
+// Publisher forwards retry events to an event bus (NATS, in-process pubsub), without this package depending on any
+// particular bus. Publish is called synchronously from the execution and should not block.
+type Publisher interface {
+	Publish(topic string, event any)
+}
+
+// PublisherFunc An adapter to allow the use of ordinary functions as Publisher
+type PublisherFunc func(topic string, event any)
+
+func (f PublisherFunc) Publish(topic string, event any) {
+	f(topic, event)
+}
+
+// Topic suffixes of the events, published under the prefix given to WithPublisher (e.g. "retry.attempt")
+const (
+	TopicAttempt = "attempt" // AttemptEvent
+	TopicError   = "error"   // ErrorEvent
+	TopicSuccess = "success" // SuccessEvent
+	TopicGiveUp  = "giveup"  // GiveUpEvent
+)
+
+// AttemptEvent published before each attempt, see OnAttempt
+type AttemptEvent struct {
+	Attempt    int
+	PolicyHash string // see Retry.PolicyHash
+}
+
+// ErrorEvent published after each failed attempt, see OnError
+type ErrorEvent struct {
+	Err        error
+	Attempt    int
+	WillRetry  bool
+	NextRetry  time.Duration
+	PolicyHash string
+}
+
+// SuccessEvent published when the callback succeeds, see OnSuccess
+type SuccessEvent struct {
+	Attempts   int
+	Elapsed    time.Duration
+	PolicyHash string
+}
+
+// GiveUpEvent published when the execution gives up, see OnGiveUp
+type GiveUpEvent struct {
+	Err        error
+	Attempts   int
+	Elapsed    time.Duration
+	PolicyHash string
+}
+
+// WithPublisher forwards the events of executions to the publisher, in addition to the hooks. Events are published
+// under topics made of the prefix ("retry" if empty) and the topic suffix, such as "retry.payments.error" for the
+// prefix "retry.payments". Follows the sampling of the hooks, see WithSampling.
+func WithPublisher(publisher Publisher, prefix string) Option {
+	if prefix == "" {
+		prefix = "retry"
+	}
+	return func(r *Retry) {
+		r.publisher = publisher
+		r.topicPrefix = prefix + "."
+	}
+}
+
+func (r *Retry) publish(topic string, event any) {
+	if r.publisher != nil {
+		r.publisher.Publish(r.topicPrefix+topic, event)
+	}
+}
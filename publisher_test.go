@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Publisher(t *testing.T) {
+
+	var topics []string
+	var events []any
+	retries := NewWithOptions(
+		WithRetries(1),
+		WithFixedBackOff(time.Millisecond),
+		WithPublisher(PublisherFunc(func(topic string, event any) {
+			topics = append(topics, topic)
+			events = append(events, event)
+		}), "retry.payments"),
+	)
+
+	errFail := errors.New("fail")
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			return errFail
+		}
+		return nil
+	})
+
+	want := []string{"retry.payments.attempt", "retry.payments.error", "retry.payments.attempt", "retry.payments.success"}
+	if len(topics) != len(want) {
+		t.Fatalf("Topics not equal, want: %v, got %v", want, topics)
+	}
+	for i := range want {
+		if topics[i] != want[i] {
+			t.Fatalf("Topics not equal, want: %v, got %v", want, topics)
+		}
+	}
+	if e, ok := events[1].(ErrorEvent); !ok || e.Err != errFail || e.Attempt != 1 || !e.WillRetry || e.NextRetry != time.Millisecond {
+		t.Fatalf("Event not expected, got %+v", events[1])
+	}
+	if e, ok := events[3].(SuccessEvent); !ok || e.Attempts != 2 {
+		t.Fatalf("Event not expected, got %+v", events[3])
+	}
+}
+
+func Test_PublisherGiveUp(t *testing.T) {
+
+	var last string
+	var event any
+	retries := NewWithOptions(WithPublisher(PublisherFunc(func(topic string, e any) {
+		last, event = topic, e
+	}), ""))
+
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	})
+	if last != "retry.giveup" {
+		t.Fatalf("Topic not equal, want: %s, got %s", "retry.giveup", last)
+	}
+	if e, ok := event.(GiveUpEvent); !ok || e.Attempts != 1 || e.Err == nil {
+		t.Fatalf("Event not expected, got %+v", event)
+	}
+}
@@ -2,124 +2,519 @@ package retry
 
 import (
 	"context"
-	"math"
+	"errors"
 	"time"
 )
 
-type BackoffStrategy interface {
-	Next(attempt int) int // next The current value of the counter and immediately updates it with the next value
-}
+var (
+	ErrStale          = errors.New("retry: inputs are stale")
+	ErrMaxElapsedTime = errors.New("retry: max elapsed time exceeded")
+)
 
-// FixedBackOffStrategy A BackoffStrategy that pauses for a fixed period of time before continuing.
-type FixedBackOffStrategy struct {
-	period int
-}
+// CancellationPolicy defines when Execute observes the cancellation of its context. In all policies the callback
+// observes cancellation through the context it receives.
+type CancellationPolicy int
 
-func (b *FixedBackOffStrategy) Next(attempt int) int {
-	return b.period
-}
+const (
+	// CancellationDefault cancellation is observed before each attempt and interrupts backoff sleeps
+	CancellationDefault CancellationPolicy = iota
+	// CancellationStrict as CancellationDefault, and also right after a failed attempt (OnError is not invoked and no
+	// sleep starts) and after each sleep, before BeforeRetry hooks run
+	CancellationStrict
+	// CancellationLoose cancellation is only observed before each attempt, backoff sleeps always run to completion
+	CancellationLoose
+)
 
-// ExponentialBackoffStrategy A BackoffStrategy that increases the back off period for each retry attempt in a given set
-// using the exponential function.
-type ExponentialBackoffStrategy struct {
-	initTime float64
-	maxTime  float64
-	factor   float64
-}
+type OnError func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration)
 
-func (b *ExponentialBackoffStrategy) Next(attempt int) int {
-	return int(math.Min(math.Pow(b.factor, float64(attempt-1))*b.initTime, b.maxTime))
-}
+// BeforeRetry is invoked after the backoff wait and before the next attempt, allowing cached resources to be
+// invalidated (drop a pooled connection, refresh an auth token, re-resolve DNS). Returning an error aborts the
+// execution with that error.
+type BeforeRetry func(ctx context.Context, err error, attempt int) error
 
-type OnError func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration)
+// AttemptContext derives the context of each attempt from the context given to Execute, allowing request-scoped
+// values (stale trace spans, per-attempt tokens) to be stripped or replaced.
+type AttemptContext func(ctx context.Context, attempt int) context.Context
 
 // Retry retries a function a given number of times until success is obtained.
 type Retry struct {
-	retries   int
-	unlimited bool
-	onError   OnError
-	Backoff   BackoffStrategy
+	retries     int
+	unlimited   bool
+	onError     OnError
+	beforeRetry BeforeRetry
+	attemptCtx  AttemptContext
+	staleAfter  time.Duration
+	refresh     func(ctx context.Context) error
+	headroom    bool
+	cancel      CancellationPolicy
+	throttle    *AdaptiveThrottle
+	waiter      Waiter
+	negative    *NegativeCache
+	retryIf     func(err error) bool
+	timeout     time.Duration
+	adaptive    *AdaptiveTimeout
+	middlewares []Middleware
+	planLatency time.Duration
+	onPlan      OnPlan
+	sampling    float64
+	stuckAfter  time.Duration
+	onStuck     OnStuck
+	onSoftLimit OnSoftLimit
+	softLimits  []float64
+	publisher   Publisher
+	topicPrefix string
+	maxElapsed  time.Duration
+	onAttempt   OnAttempt
+	onSuccess   OnSuccess
+	onGiveUp    OnGiveUp
+	tracer      Tracer
+	counters    *counters
+	hints       []HintExtractor
+	hedgeDelay  time.Duration
+	maxHedges   int
+	clock       Clock
+	frozen      bool
+	policyHash  string
+	backoff     BackoffStrategy // snapshot of Backoff taken by NewWithOptions
+	Backoff     BackoffStrategy
 }
 
 // New initialize new Retry
 func New(numberOfRetries int, onError OnError) *Retry {
-	strategy := &Retry{onError: onError}
+	strategy := &Retry{onError: onError, counters: &counters{}, sampling: 1}
 
 	// default backoff
-	strategy.SetFixedBackOff(1000)
-	strategy.SetNumberOfRetries(numberOfRetries)
+	strategy.apply(WithFixedBackOff(time.Second), WithRetries(numberOfRetries))
 
 	return strategy
 }
 
+// apply the options to this Retry. Panics if the Retry is frozen, as it may be in use by concurrent executions.
+func (r *Retry) apply(opts ...Option) {
+	if r.frozen {
+		panic("retry: cannot modify a frozen Retry")
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// backoffStrategy returns the BackoffStrategy in use
+func (r *Retry) backoffStrategy() BackoffStrategy {
+	if r.frozen {
+		return r.backoff
+	}
+	return r.Backoff
+}
+
 // SetNumberOfRetries Set the number of retries that are to be attempted before giving up. To try forever, use -1.
+//
+// Deprecated: use NewWithOptions with WithRetries.
 func (r *Retry) SetNumberOfRetries(retries int) {
-	r.retries = retries
-	r.unlimited = retries < 0
+	r.apply(WithRetries(retries))
 }
 
+// SetAttemptTimeout Set the maximum duration of each attempt, the callback context is canceled when exceeded. Use 0
+// to disable.
+//
+// Deprecated: use NewWithOptions with WithAttemptTimeout.
+func (r *Retry) SetAttemptTimeout(timeout time.Duration) {
+	r.apply(WithAttemptTimeout(timeout))
+}
+
+// SetMaxElapsedTime Set the total budget of an execution, including backoff waits. No new attempt is scheduled if it
+// would start after the budget is exhausted, even with unlimited retries; the execution gives up with an error
+// matching ErrMaxElapsedTime. Use 0 to disable.
+//
+// Deprecated: use NewWithOptions with WithMaxElapsedTime.
+func (r *Retry) SetMaxElapsedTime(maxElapsed time.Duration) {
+	r.apply(WithMaxElapsedTime(maxElapsed))
+}
+
+// SetRetryIf Set the predicate that classifies errors as retryable. Errors for which it returns false, as well as
+// errors marked with Unrecoverable, abort the execution immediately. By default, any error is retryable.
+//
+// Deprecated: use NewWithOptions with WithRetryIf.
+func (r *Retry) SetRetryIf(retryIf func(err error) bool) {
+	r.apply(WithRetryIf(retryIf))
+}
+
+// SetBeforeRetry Set the hook invoked before each retry attempt. See BeforeRetry.
+//
+// Deprecated: use NewWithOptions with WithBeforeRetry.
+func (r *Retry) SetBeforeRetry(beforeRetry BeforeRetry) {
+	r.apply(WithBeforeRetry(beforeRetry))
+}
+
+// SetAttemptContext Set the function used to derive the context of each attempt. See AttemptContext.
+//
+// Deprecated: use NewWithOptions with WithAttemptContext.
+func (r *Retry) SetAttemptContext(attemptCtx AttemptContext) {
+	r.apply(WithAttemptContext(attemptCtx))
+}
+
+// SetStaleAfter Guard against retries based on stale inputs. If the inputs captured at Execute start (or at the last
+// refresh) are older than staleAfter by the time a retry would fire, the refresh callback is invoked. With a nil
+// refresh, or if the refresh fails, the execution is aborted (ErrStale or the refresh error). Use 0 to disable.
+//
+// Deprecated: use NewWithOptions with WithStaleAfter.
+func (r *Retry) SetStaleAfter(staleAfter time.Duration, refresh func(ctx context.Context) error) {
+	r.apply(WithStaleAfter(staleAfter, refresh))
+}
+
+// SetAttemptDeadlineFromBackoff When enabled, each attempt that may be followed by a retry has its context deadline
+// set to the backoff delay of that attempt, and the delay counts from the start of the attempt, so a hung attempt can
+// never push the schedule later than planned. Attempts with no delay (e.g. FixedBackOff(0)) have no deadline.
+//
+// Deprecated: use NewWithOptions with WithAttemptDeadlineFromBackoff.
+func (r *Retry) SetAttemptDeadlineFromBackoff(enabled bool) {
+	r.apply(WithAttemptDeadlineFromBackoff(enabled))
+}
+
+// SetCancellationPolicy Set when cancellation of the context is observed. See CancellationPolicy.
+//
+// Deprecated: use NewWithOptions with WithCancellationPolicy.
+func (r *Retry) SetCancellationPolicy(policy CancellationPolicy) {
+	r.apply(WithCancellationPolicy(policy))
+}
+
+// SetThrottle Set the client-side adaptive throttle. An attempt rejected by the throttle is not sent and the execution
+// gives up immediately with ErrThrottled, without spending backoff time.
+//
+// Deprecated: use NewWithOptions with WithThrottle.
+func (r *Retry) SetThrottle(throttle *AdaptiveThrottle) {
+	r.apply(WithThrottle(throttle))
+}
+
+// SetWaiter Set the mechanism used to wait for backoff delays. Defaults to TimerWaiter, or JSWaiter under
+// GOOS=js GOARCH=wasm.
+//
+// Deprecated: use NewWithOptions with WithWaiter.
+func (r *Retry) SetWaiter(waiter Waiter) {
+	r.apply(WithWaiter(waiter))
+}
+
+// SetFixedBackOff
+// period - in milliseconds for which the execution is suspended between attempts
+//
+// Deprecated: use NewWithOptions with WithFixedBackOff.
 func (r *Retry) SetFixedBackOff(period int) {
-	r.Backoff = &FixedBackOffStrategy{
-		period: period,
-	}
+	r.apply(WithFixedBackOff(time.Duration(period) * time.Millisecond))
 }
 
 // SetExponentialBackoff
 // initTime - in milliseconds for which the execution is suspended after the first attempt
 // maxTime - in milliseconds for which the execution can be suspended
 // factor - is the base of the power by which the waiting time increases
+//
+// Deprecated: use NewWithOptions with WithExponentialBackoff.
 func (r *Retry) SetExponentialBackoff(initTime int, maxTime int, factor float64) {
-	r.Backoff = &ExponentialBackoffStrategy{
-		initTime: float64(initTime),
-		maxTime:  float64(maxTime),
-		factor:   factor,
-	}
+	r.apply(WithExponentialBackoff(time.Duration(initTime)*time.Millisecond, time.Duration(maxTime)*time.Millisecond, factor))
+}
+
+// SetLinearBackoff
+// initTime - in milliseconds for which the execution is suspended after the first attempt
+// increment - in milliseconds added to the waiting time on each attempt
+// maxTime - in milliseconds for which the execution can be suspended
+//
+// Deprecated: use NewWithOptions with WithLinearBackoff.
+func (r *Retry) SetLinearBackoff(initTime int, increment int, maxTime int) {
+	r.apply(WithLinearBackoff(time.Duration(initTime)*time.Millisecond, time.Duration(increment)*time.Millisecond, time.Duration(maxTime)*time.Millisecond))
+}
+
+// SetFibonacciBackoff
+// initTime - in milliseconds for which the execution is suspended after the first attempt
+// maxTime - in milliseconds for which the execution can be suspended
+//
+// Deprecated: use NewWithOptions with WithFibonacciBackoff.
+func (r *Retry) SetFibonacciBackoff(initTime int, maxTime int) {
+	r.apply(WithFibonacciBackoff(time.Duration(initTime)*time.Millisecond, time.Duration(maxTime)*time.Millisecond))
+}
+
+// SetHashedJitter Spread the current backoff by a stable offset in milliseconds, in the range [0, spread), derived
+// from the job key. See HashedJitterBackoffStrategy.
+//
+// Deprecated: use NewWithOptions with WithHashedJitter.
+func (r *Retry) SetHashedJitter(key string, spread int) {
+	r.apply(WithHashedJitter(key, time.Duration(spread)*time.Millisecond))
 }
 
 // Execute  Keep retrying a callback with a potentially varying wait on each iteration, until one of the following happens:
 // - the callback returns nil
-// - the number of retries is exceeded, retuning last error
+// - the number of retries or the max elapsed time is exceeded
+// - the callback returns a non-retryable error (see SetRetryIf and Unrecoverable)
+// - the execution is aborted (context canceled, stale inputs, hook error, throttled)
+//
+// When giving up after at least one attempt, returns an *Error with the history of the attempts, matching (errors.Is
+// and errors.As) any of the attempt errors and the abort reason.
 func (r *Retry) Execute(ctx context.Context, callback func(ctx context.Context, attempt int) error) error {
+	started := r.now()
+	if r.counters != nil {
+		r.counters.executions.Add(1)
+	}
+
+	ctx, warnings := withWarnings(ctx)
+	reported := warnings.len()
+	sampled := r.sample()
+	attempts, err := r.execute(ctx, callback, started, sampled)
+	r.finish(ctx, err, attempts, started, warnings.since(reported), sampled)
+	return err
+}
+
+// finish records the outcome of an execution and the warnings reported during it, invoking the OnSuccess or OnGiveUp
+// hooks
+func (r *Retry) finish(ctx context.Context, err error, attempts int, started time.Time, warnings []error, sampled bool) {
+	if r.counters != nil {
+		r.counters.warnings.Add(int64(len(warnings)))
+	}
+	if e, ok := err.(*Error); ok {
+		e.warnings = warnings
+		e.policyHash = r.policyHash
+	}
+	if err == nil {
+		if r.counters != nil {
+			r.counters.successes.Add(1)
+		}
+		if sampled || attempts > 1 {
+			elapsed := r.since(started)
+			if r.onSuccess != nil {
+				r.onSuccess(ctx, attempts, elapsed)
+			}
+			r.publish(TopicSuccess, SuccessEvent{Attempts: attempts, Elapsed: elapsed, PolicyHash: r.policyHash})
+		}
+	} else {
+		if r.counters != nil {
+			r.counters.failures.Add(1)
+		}
+		elapsed := r.since(started)
+		if r.onGiveUp != nil {
+			r.onGiveUp(ctx, err, attempts, elapsed)
+		}
+		r.publish(TopicGiveUp, GiveUpEvent{Err: err, Attempts: attempts, Elapsed: elapsed, PolicyHash: r.policyHash})
+	}
+}
+
+// notifyAttempt invokes the OnAttempt hook and publishes the event
+func (r *Retry) notifyAttempt(ctx context.Context, attempt int) {
+	if r.onAttempt != nil {
+		r.onAttempt(ctx, attempt)
+	}
+	r.publish(TopicAttempt, AttemptEvent{Attempt: attempt, PolicyHash: r.policyHash})
+}
+
+// notifyError invokes the OnError hook and publishes the event
+func (r *Retry) notifyError(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+	if r.onError != nil {
+		r.onError(ctx, err, attempt, willRetry, nextRetry)
+	}
+	r.publish(TopicError, ErrorEvent{Err: err, Attempt: attempt, WillRetry: willRetry, NextRetry: nextRetry, PolicyHash: r.policyHash})
+}
+
+// execute runs the retry loop, returning the number of attempts made
+func (r *Retry) execute(ctx context.Context, callback func(ctx context.Context, attempt int) error, started time.Time, sampled bool) (int, error) {
 	attempt := 0
+	capturedAt := started
+	noRetry := IsNoRetry(ctx)
+	retries, unlimited := r.plan(ctx, started)
+	ctx = withMemo(ctx)
+	failures := &Error{}
+	attemptFn := r.chain(callback)
+	strategy := executionBackoffOf(r.backoffStrategy())
+	softLimits := 0
 	for {
 		// Return immediately if ctx is canceled
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return attempt, failures.abort(ctx.Err(), r.since(started))
 		default:
 		}
 
 		attempt++
-		err := callback(ctx, attempt)
+		attemptCtx := ctx
+		if r.attemptCtx != nil {
+			attemptCtx = r.attemptCtx(ctx, attempt)
+		}
+		willRetry := !noRetry && (unlimited || attempt <= retries)
+		// the delay is not known before the attempt fails, the deadline is based on the delay computed ahead of time
+		deadline := time.Duration(-1)
+		if r.headroom && willRetry {
+			if d := strategy.NextDelay(attempt, nil); d > 0 {
+				deadline = d
+			}
+		}
+		if r.throttle != nil && !r.throttle.Allow() {
+			r.notifyError(ctx, ErrThrottled, attempt, false, time.Duration(0))
+			return attempt, failures.abort(ErrThrottled, r.since(started))
+		}
+
+		timeout := r.attemptTimeout()
+		if timeout < 0 || (deadline >= 0 && deadline < timeout) {
+			timeout = deadline
+		}
+		// attempts after a failure are always recorded
+		sampled = sampled || attempt > 1
+		if sampled {
+			r.notifyAttempt(ctx, attempt)
+		}
+		if r.counters != nil {
+			r.counters.attempts.Add(1)
+		}
+		var end func(err error)
+		if r.tracer != nil && sampled {
+			attemptCtx, end = r.tracer.StartAttempt(attemptCtx, attempt)
+		}
+		attemptStarted := r.now()
+		err := r.runAttempt(attemptCtx, attempt, timeout, attemptFn)
+		if err == nil && r.adaptive != nil {
+			r.adaptive.Observe(r.since(attemptStarted))
+		}
+		if end != nil {
+			end(err)
+		}
+		if r.throttle != nil {
+			r.throttle.Record(err == nil)
+		}
 		if err == nil {
 			break
 		}
+		failures.add(err)
 
-		if r.unlimited || attempt <= r.retries {
-
-			next := time.Duration(r.Backoff.Next(attempt)) * time.Millisecond
+		if r.cancel == CancellationStrict && ctx.Err() != nil {
+			return attempt, failures.abort(ctx.Err(), r.since(started))
+		}
 
-			if r.onError != nil {
-				r.onError(ctx, err, attempt, true, next)
+		hint, hasHint := r.hint(err)
+		if hasHint && hint.Retryable != RetryUnknown && !IsUnrecoverable(err) {
+			willRetry = willRetry && hint.Retryable == RetryAllowed
+		} else {
+			willRetry = willRetry && r.isRetryable(err)
+		}
+		next := time.Duration(-1)
+		if delay, ok := RetryAfterDelay(err); ok {
+			next = delay
+		} else if hasHint && hint.Delay > 0 {
+			next = hint.Delay
+		} else if willRetry {
+			next = strategy.NextDelay(attempt, err)
+			if r.headroom {
+				// the delay counts from the start of the attempt, keeping the schedule
+				next -= r.since(attemptStarted)
+				if next < 0 {
+					next = 0
+				}
 			}
+		}
 
-			t := time.NewTimer(next)
-			select {
-			case <-ctx.Done():
-				t.Stop()
-				return ctx.Err()
-			case <-t.C:
-				continue
-			}
+		elapsed := r.since(started)
+		if willRetry {
+			softLimits = r.softLimit(ctx, softLimits, elapsed+next)
 		} else {
-			// the number of retries is exceeded.
-			if r.onError != nil {
-				r.onError(ctx, err, attempt, false, time.Duration(0))
+			softLimits = r.softLimit(ctx, softLimits, elapsed)
+		}
+
+		var cause error
+		if willRetry && r.maxElapsed > 0 && elapsed+next > r.maxElapsed {
+			willRetry = false
+			cause = ErrMaxElapsedTime
+		}
+
+		if !willRetry {
+			// the number of retries or the time budget is exceeded, or the error is not retryable.
+			r.notifyError(ctx, err, attempt, false, time.Duration(0))
+			return attempt, failures.abort(cause, r.since(started))
+		}
+
+		r.notifyError(ctx, err, attempt, true, next)
+		if r.counters != nil {
+			r.counters.retries.Add(1)
+			r.counters.backoff.Add(int64(next))
+		}
+
+		if err := r.sleep(ctx, next); err != nil {
+			return attempt, failures.abort(err, r.since(started))
+		}
+
+		if r.staleAfter > 0 && r.since(capturedAt) > r.staleAfter {
+			if r.refresh == nil {
+				return attempt, failures.abort(ErrStale, r.since(started))
+			}
+			if refreshErr := r.refresh(ctx); refreshErr != nil {
+				return attempt, failures.abort(refreshErr, r.since(started))
+			}
+			capturedAt = r.now()
+		}
+
+		if r.beforeRetry != nil {
+			if hookErr := r.beforeRetry(ctx, err, attempt+1); hookErr != nil {
+				return attempt, failures.abort(hookErr, r.since(started))
 			}
-			return err
 		}
 	}
 
 	// the callback returns nil
+	return attempt, nil
+}
+
+// isRetryable classifies the error returned by the callback
+func (r *Retry) isRetryable(err error) bool {
+	if IsUnrecoverable(err) {
+		return false
+	}
+	return r.retryIf == nil || r.retryIf(err)
+}
+
+// sleep waits for the backoff delay, observing cancellation according to the CancellationPolicy
+func (r *Retry) sleep(ctx context.Context, d time.Duration) error {
+	if r.cancel == CancellationLoose {
+		return r.wait(uncancelable{ctx}, d)
+	}
+
+	if err := r.wait(ctx, d); err != nil {
+		return err
+	}
+
+	if r.cancel == CancellationStrict {
+		return ctx.Err()
+	}
 	return nil
 }
+
+// wait waits for the duration using the configured Waiter
+func (r *Retry) wait(ctx context.Context, d time.Duration) error {
+	if r.waiter != nil {
+		return r.waiter.Wait(ctx, d)
+	}
+	if r.clock != nil {
+		return r.clock.Wait(ctx, d)
+	}
+	return defaultWaiter().Wait(ctx, d)
+}
+
+// runAttempt runs an attempt under the watchdog, recording its cost
+func (r *Retry) runAttempt(ctx context.Context, attempt int, timeout time.Duration, callback func(ctx context.Context, attempt int) error) error {
+	ctx, h := withHold(ctx)
+	watchCtx, stopWatch := r.watch(ctx, attempt)
+	err := invoke(r.withCost(watchCtx, attempt), attempt, timeout, callback)
+	h.end()
+	stopWatch()
+	return err
+}
+
+// invoke calls the callback, bounded by the given timeout when not negative, and runs the attempt cleanups
+func invoke(ctx context.Context, attempt int, timeout time.Duration, callback func(ctx context.Context, attempt int) error) error {
+	ctx, c := withCleanups(ctx)
+	defer c.run()
+
+	if timeout < 0 {
+		return callback(ctx, attempt)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancelAfterHolds(ctx, cancel)
+	return callback(ctx, attempt)
+}
+
+// clone returns a shallow copy of this Retry
+func (r *Retry) clone() *Retry {
+	c := *r
+	return &c
+}
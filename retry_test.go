@@ -226,3 +226,474 @@ func Test_ExponentialBackOffError(t *testing.T) {
 		t.Fatalf("nextRetry time error , want: %d, got %d", 1500, sumNextRetry)
 	}
 }
+
+func Test_BeforeRetry(t *testing.T) {
+
+	var attempts []int
+	closer := &idleCloser{}
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetBeforeRetry(ChainBeforeRetry(CloseIdleConnections(closer), func(ctx context.Context, err error, attempt int) error {
+		attempts = append(attempts, attempt)
+		return nil
+	}))
+
+	err := retries.Execute(context.Background(), executeFn)
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if closer.count != 3 {
+		t.Fatalf("CloseIdleConnections count not equal, want: %d, got %d", 3, closer.count)
+	}
+
+	if len(attempts) != 3 || attempts[0] != 2 || attempts[2] != 4 {
+		t.Fatalf("BeforeRetry attempts not equal, want: %v, got %v", []int{2, 3, 4}, attempts)
+	}
+}
+
+func Test_BeforeRetryAbort(t *testing.T) {
+
+	refreshErr := errors.New("refresh")
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetBeforeRetry(RefreshToken(func(ctx context.Context) error {
+		return refreshErr
+	}))
+
+	err := retries.Execute(context.Background(), executeFn)
+
+	if !errors.Is(err, refreshErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", refreshErr, err)
+	}
+}
+
+type idleCloser struct {
+	count int
+}
+
+func (c *idleCloser) CloseIdleConnections() {
+	c.count++
+}
+
+type ctxKey string
+
+func Test_AttemptContext(t *testing.T) {
+
+	ctx := context.WithValue(context.Background(), ctxKey("span"), "attempt-1")
+	ctx = context.WithValue(ctx, ctxKey("tenant"), "acme")
+
+	var spans []any
+	var tenants []any
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetAttemptContext(func(ctx context.Context, attempt int) context.Context {
+		if attempt == 1 {
+			return ctx
+		}
+		return WithoutValues(ctxKey("span"))(ctx, attempt)
+	})
+
+	err := retries.Execute(ctx, func(ctx context.Context, attempt int) error {
+		spans = append(spans, ctx.Value(ctxKey("span")))
+		tenants = append(tenants, ctx.Value(ctxKey("tenant")))
+		return executeFn(ctx, attempt)
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if spans[0] != "attempt-1" || spans[1] != nil || spans[3] != nil {
+		t.Fatalf("Span values not equal, got %v", spans)
+	}
+
+	if tenants[0] != "acme" || tenants[3] != "acme" {
+		t.Fatalf("Tenant values not equal, got %v", tenants)
+	}
+}
+
+func Test_StaleAfter(t *testing.T) {
+
+	countRefresh := 0
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(5)
+	retries.SetStaleAfter(8*time.Millisecond, func(ctx context.Context) error {
+		countRefresh++
+		return nil
+	})
+
+	err := retries.Execute(context.Background(), executeFn)
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	// refreshed before retries 2 (10ms) and 4 (20ms)
+	if countRefresh < 1 || countRefresh > 2 {
+		t.Fatalf("Count refresh not expected, got %d", countRefresh)
+	}
+}
+
+func Test_StaleAfterAbort(t *testing.T) {
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(5)
+	retries.SetStaleAfter(time.Millisecond, nil)
+
+	err := retries.Execute(context.Background(), executeFn)
+
+	if !errors.Is(err, ErrStale) {
+		t.Fatalf("Error not equal, want: %v, got %v", ErrStale, err)
+	}
+}
+
+func Test_HashedJitter(t *testing.T) {
+
+	a := NewHashedJitterBackoffStrategy(NewFixedBackOff(time.Second), "nightly-sync:tenant-a", time.Minute)
+	b := NewHashedJitterBackoffStrategy(NewFixedBackOff(time.Second), "nightly-sync:tenant-a", time.Minute)
+	c := NewHashedJitterBackoffStrategy(NewFixedBackOff(time.Second), "nightly-sync:tenant-b", time.Minute)
+
+	if a.NextDelay(1, nil) != b.NextDelay(1, nil) || a.NextDelay(2, nil) != b.NextDelay(2, nil) {
+		t.Fatalf("Hashed jitter not stable, got %s and %s", a.NextDelay(1, nil), b.NextDelay(1, nil))
+	}
+
+	if a.NextDelay(1, nil) == c.NextDelay(1, nil) {
+		t.Fatalf("Hashed jitter not spread, got %s for both keys", a.NextDelay(1, nil))
+	}
+
+	if d := a.NextDelay(1, nil); d < time.Second || d >= 61*time.Second {
+		t.Fatalf("Hashed jitter out of range, got %s", d)
+	}
+}
+
+func Test_AttemptDeadlineFromBackoff(t *testing.T) {
+
+	var deadlines []bool
+
+	retries := New(2, nil)
+	retries.SetFixedBackOff(5)
+	retries.SetAttemptDeadlineFromBackoff(true)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		_, ok := ctx.Deadline()
+		deadlines = append(deadlines, ok)
+		if attempt == 1 {
+			// hung attempt
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return executeFn(ctx, attempt)
+	})
+
+	if err == nil {
+		t.Fatalf("Error expected")
+	}
+
+	// the last attempt is not followed by a retry
+	if len(deadlines) != 3 || !deadlines[0] || !deadlines[1] || deadlines[2] {
+		t.Fatalf("Deadlines not equal, want: %v, got %v", []bool{true, true, false}, deadlines)
+	}
+}
+
+func Test_AttemptDeadlineFromBackoffSchedule(t *testing.T) {
+
+	var starts []time.Time
+
+	retries := New(1, nil)
+	retries.SetFixedBackOff(50)
+	retries.SetAttemptDeadlineFromBackoff(true)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		starts = append(starts, time.Now())
+		if attempt == 1 {
+			// hung attempt, cut off at the delay
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	// the next attempt starts one delay after the hung one, not two
+	if gap := starts[1].Sub(starts[0]); gap < 50*time.Millisecond || gap >= 90*time.Millisecond {
+		t.Fatalf("Gap between attempts not expected, want ~%s, got %s", 50*time.Millisecond, gap)
+	}
+}
+
+func Test_AttemptDeadlineFromBackoffZeroDelay(t *testing.T) {
+
+	retries := New(1, nil)
+	retries.SetFixedBackOff(0)
+	retries.SetAttemptDeadlineFromBackoff(true)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if _, ok := ctx.Deadline(); ok {
+			t.Fatalf("Deadline not expected without delay")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+}
+
+func Test_AttemptDeadlineFromBackoffErrorAwareStrategy(t *testing.T) {
+
+	var waits []time.Duration
+
+	retries := NewWithOptions(
+		WithRetries(1),
+		WithAttemptDeadlineFromBackoff(true),
+		WithBackoff(BackoffFunc(func(attempt int, err error) time.Duration {
+			if errors.Is(err, customErr) {
+				return time.Second
+			}
+			return time.Hour
+		})),
+		WithWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		})),
+	)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			return customErr
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	// the sleep after the failure is computed with the error of the attempt
+	if len(waits) != 1 || waits[0] > time.Second || waits[0] < time.Second/2 {
+		t.Fatalf("Waits not expected, want: ~%v, got %v", time.Second, waits)
+	}
+}
+
+func Test_CancellationPolicy(t *testing.T) {
+
+	run := func(policy CancellationPolicy) (countError int, elapsed time.Duration, err error) {
+		ctx, ctxCancel := context.WithCancel(context.Background())
+
+		retries := New(3, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			countError++
+		})
+		retries.SetFixedBackOff(20)
+		retries.SetCancellationPolicy(policy)
+
+		start := time.Now()
+		err = retries.Execute(ctx, func(ctx context.Context, attempt int) error {
+			if attempt == 1 {
+				ctxCancel()
+			}
+			return customErr
+		})
+		return countError, time.Since(start), err
+	}
+
+	// default: OnError invoked, sleep interrupted
+	countError, elapsed, err := run(CancellationDefault)
+	if !errors.Is(err, context.Canceled) || countError != 1 || elapsed >= 20*time.Millisecond {
+		t.Fatalf("CancellationDefault not respected, err: %v, countError: %d, elapsed: %s", err, countError, elapsed)
+	}
+
+	// strict: returns right after the failed attempt
+	countError, _, err = run(CancellationStrict)
+	if !errors.Is(err, context.Canceled) || countError != 0 {
+		t.Fatalf("CancellationStrict not respected, err: %v, countError: %d", err, countError)
+	}
+
+	// loose: sleep runs to completion, observed before the next attempt
+	countError, elapsed, err = run(CancellationLoose)
+	if !errors.Is(err, context.Canceled) || countError != 1 || elapsed < 20*time.Millisecond {
+		t.Fatalf("CancellationLoose not respected, err: %v, countError: %d, elapsed: %s", err, countError, elapsed)
+	}
+}
+
+func Test_NoRetryContext(t *testing.T) {
+
+	countError := 0
+	willRetryAny := false
+
+	retries := New(3, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		countError++
+		willRetryAny = willRetryAny || willRetry
+	})
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(NoRetryContext(context.Background()), executeFn)
+
+	if !errors.Is(err, customErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", customErr, err)
+	}
+
+	if countError != 1 || willRetryAny {
+		t.Fatalf("No retry expected, countError: %d, willRetry: %v", countError, willRetryAny)
+	}
+}
+
+func Test_Waiter(t *testing.T) {
+
+	var waits []time.Duration
+
+	retries := New(3, nil)
+	retries.SetExponentialBackoff(500, 5000, 2)
+	retries.SetWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}))
+
+	err := retries.Execute(context.Background(), executeFn)
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}
+	if len(waits) != len(want) || waits[0] != want[0] || waits[1] != want[1] || waits[2] != want[2] {
+		t.Fatalf("Waits not equal, want: %v, got %v", want, waits)
+	}
+}
+
+func Test_Unrecoverable(t *testing.T) {
+
+	countError := 0
+	willRetryAny := false
+
+	retries := New(3, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		countError++
+		willRetryAny = willRetryAny || willRetry
+	})
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return Unrecoverable(customErr)
+	})
+
+	if !errors.Is(err, ErrUnrecoverable) || !errors.Is(err, customErr) {
+		t.Fatalf("Unrecoverable error expected, got %v", err)
+	}
+
+	if countError != 1 || willRetryAny {
+		t.Fatalf("No retry expected, countError: %d, willRetry: %v", countError, willRetryAny)
+	}
+
+	if Permanent(nil) != nil {
+		t.Fatalf("Permanent(nil) should be nil")
+	}
+}
+
+func Test_RetryIf(t *testing.T) {
+
+	validationErr := errors.New("validation")
+	calls := 0
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetRetryIf(func(err error) bool {
+		return !errors.Is(err, validationErr)
+	})
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		calls++
+		if attempt == 2 {
+			return validationErr
+		}
+		return customErr
+	})
+
+	if !errors.Is(err, validationErr) {
+		t.Fatalf("Error not equal, want: %v, got %v", validationErr, err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 2, calls)
+	}
+}
+
+func Test_AttemptTimeout(t *testing.T) {
+
+	retries := New(2, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetAttemptTimeout(5 * time.Millisecond)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt < 3 {
+			// hung attempt
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+}
+
+func Test_MaxElapsedTime(t *testing.T) {
+
+	countError := 0
+	lastWillRetry := true
+
+	retries := New(-1, func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+		countError++
+		lastWillRetry = willRetry
+	})
+	retries.SetFixedBackOff(10)
+	retries.SetMaxElapsedTime(35 * time.Millisecond)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return customErr
+	})
+
+	if !errors.Is(err, ErrMaxElapsedTime) || !errors.Is(err, customErr) {
+		t.Fatalf("ErrMaxElapsedTime expected, got %v", err)
+	}
+
+	// attempts at 0, 10, 20, 30ms, the next one would start after the budget
+	if countError < 2 || countError > 4 || lastWillRetry {
+		t.Fatalf("Give up not expected, countError: %d, willRetry: %v", countError, lastWillRetry)
+	}
+}
+
+func Test_RetryAfter(t *testing.T) {
+
+	var waits []time.Duration
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(100)
+	retries.SetWaiter(WaiterFunc(func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}))
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			return RetryAfter(customErr, 3*time.Second)
+		}
+		return executeFn(ctx, attempt)
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	want := []time.Duration{3 * time.Second, 100 * time.Millisecond, 100 * time.Millisecond}
+	if len(waits) != len(want) || waits[0] != want[0] || waits[1] != want[1] {
+		t.Fatalf("Waits not equal, want: %v, got %v", want, waits)
+	}
+}
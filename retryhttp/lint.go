@@ -0,0 +1,39 @@
+package retryhttp
+
+import (
+	"net/http"
+
+	"github.com/nidorx/retry"
+)
+
+// LintNonIdempotentMethod lint warning code for a Transport retrying non-idempotent methods, which may apply a
+// request more than once
+const LintNonIdempotentMethod = "non-idempotent-method"
+
+// idempotentMethods methods defined as idempotent by RFC 9110
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// Lint returns the warnings of retry.Lint for the Retry of the Transport, and a warning for each non-idempotent
+// method (e.g. POST, PATCH) it retries.
+func Lint(t *Transport) []retry.LintWarning {
+	var warnings []retry.LintWarning
+	if t.Retry != nil {
+		warnings = retry.Lint(t.Retry)
+	}
+	for _, method := range t.Methods {
+		if !idempotentMethods[method] {
+			warnings = append(warnings, retry.LintWarning{
+				Code:    LintNonIdempotentMethod,
+				Message: "retrying " + method + " requests may apply them more than once",
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,28 @@
+package retryhttp
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nidorx/retry"
+)
+
+func Test_Lint(t *testing.T) {
+
+	transport := NewTransport(nil, retry.NewWithOptions(retry.WithRetries(3)))
+	if warnings := Lint(transport); len(warnings) != 0 {
+		t.Fatalf("Warnings not expected with the default methods, got %v", warnings)
+	}
+
+	transport.Methods = []string{http.MethodGet, http.MethodPost, http.MethodPatch}
+	warnings := Lint(transport)
+	if len(warnings) != 2 || warnings[0].Code != LintNonIdempotentMethod || warnings[1].Code != LintNonIdempotentMethod {
+		t.Fatalf("Warnings not equal, got %v", warnings)
+	}
+
+	transport.Retry = retry.NewWithOptions(retry.WithRetries(-1))
+	transport.Methods = nil
+	if warnings := Lint(transport); len(warnings) != 1 || warnings[0].Code != retry.LintUnlimitedRetries {
+		t.Fatalf("Warnings of the Retry not expected, got %v", warnings)
+	}
+}
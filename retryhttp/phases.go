@@ -0,0 +1,110 @@
+package retryhttp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Phases The timings of the phases of an HTTP attempt, zero for the phases that did not happen (e.g. DNS and connect
+// on a reused connection)
+type Phases struct {
+	DNS     time.Duration // DNS lookup
+	Connect time.Duration // TCP connect
+	TLS     time.Duration // TLS handshake
+	TTFB    time.Duration // from the start of the attempt to the first response byte
+	Reused  bool          // whether the connection was reused
+}
+
+// AttemptError wraps the error of a failed attempt with the timings of its phases, so retries caused by connect
+// timeouts are distinguishable from slow responses in the attempt history (retry.Error.Attempts)
+type AttemptError struct {
+	Err    error
+	Phases Phases
+}
+
+func (e *AttemptError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AttemptError) Unwrap() error {
+	return e.Err
+}
+
+// PhasesOf returns the phase timings attached to the error of an attempt
+func PhasesOf(err error) (Phases, bool) {
+	var e *AttemptError
+	if errors.As(err, &e) {
+		return e.Phases, true
+	}
+	return Phases{}, false
+}
+
+// phaseTrace records the phases of an attempt with httptrace
+type phaseTrace struct {
+	mu           sync.Mutex
+	started      time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	phases       Phases
+}
+
+func newPhaseTrace(ctx context.Context) (context.Context, *phaseTrace) {
+	p := &phaseTrace{started: time.Now()}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			p.mark(&p.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			p.measure(&p.dnsStart, &p.phases.DNS)
+		},
+		ConnectStart: func(network, addr string) {
+			p.mark(&p.connectStart)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			p.measure(&p.connectStart, &p.phases.Connect)
+		},
+		TLSHandshakeStart: func() {
+			p.mark(&p.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			p.measure(&p.tlsStart, &p.phases.TLS)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			p.mu.Lock()
+			p.phases.Reused = info.Reused
+			p.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			p.measure(&p.started, &p.phases.TTFB)
+		},
+	}), p
+}
+
+func (p *phaseTrace) mark(t *time.Time) {
+	p.mu.Lock()
+	*t = time.Now()
+	p.mu.Unlock()
+}
+
+func (p *phaseTrace) measure(start *time.Time, d *time.Duration) {
+	p.mu.Lock()
+	if !start.IsZero() {
+		*d = time.Since(*start)
+	}
+	p.mu.Unlock()
+}
+
+// wrap attaches the phases to the error of the attempt
+func (p *phaseTrace) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &AttemptError{Err: err, Phases: p.phases}
+}
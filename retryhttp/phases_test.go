@@ -0,0 +1,81 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nidorx/retry"
+)
+
+func Test_TransportTracePhases(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var phases []Phases
+	r := retry.NewWithOptions(
+		retry.WithRetries(2),
+		retry.WithFixedBackOff(time.Millisecond),
+		retry.WithOnError(func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			p, ok := PhasesOf(err)
+			if !ok {
+				t.Fatalf("Phases not attached to %v", err)
+			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("Status error not wrapped, got %v", err)
+			}
+			phases = append(phases, p)
+		}),
+	)
+	transport := NewTransport(nil, r)
+	transport.TracePhases = true
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(phases) != 3 {
+		t.Fatalf("Phases not equal, want: %d, got %d", 3, len(phases))
+	}
+	if phases[0].Reused || phases[0].Connect <= 0 || phases[0].TTFB <= 0 {
+		t.Fatalf("Phases of the first attempt not expected, got %+v", phases[0])
+	}
+	if !phases[1].Reused || phases[1].Connect != 0 {
+		t.Fatalf("Phases of the second attempt not expected, got %+v", phases[1])
+	}
+}
+
+func Test_TransportTracePhasesConnectError(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	transport := NewTransport(nil, newRetry(1, nil))
+	transport.TracePhases = true
+
+	_, err = (&http.Client{Transport: transport}).Get("http://" + addr)
+	var retryErr *retry.Error
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	for _, attemptErr := range retryErr.Attempts() {
+		p, ok := PhasesOf(attemptErr)
+		if !ok || p.Connect <= 0 || p.TTFB != 0 {
+			t.Fatalf("Phases not expected, got %+v", p)
+		}
+	}
+}
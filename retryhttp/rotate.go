@@ -0,0 +1,57 @@
+package retryhttp
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Selector returns the RoundTripper used for an attempt, allowing the egress path (proxy, source interface or IP) to
+// be rotated between attempts. Returning nil uses the Base of the Transport.
+type Selector func(req *http.Request, attempt int) http.RoundTripper
+
+// RotateProxies returns a Selector that cycles through the proxies, one per attempt. Each proxy gets its own clone of
+// base (http.DefaultTransport if nil), so connections are not shared between proxies.
+func RotateProxies(base *http.Transport, proxies ...*url.URL) Selector {
+	transports := make([]http.RoundTripper, len(proxies))
+	for i, proxy := range proxies {
+		t := cloneTransport(base)
+		t.Proxy = http.ProxyURL(proxy)
+		transports[i] = t
+	}
+	return rotate(transports)
+}
+
+// RotateLocalAddrs returns a Selector that cycles through the local addresses used to dial, one per attempt, for
+// multi-homed hosts where an egress path may be blocked. Each address gets its own clone of base
+// (http.DefaultTransport if nil).
+func RotateLocalAddrs(base *http.Transport, addrs ...net.Addr) Selector {
+	transports := make([]http.RoundTripper, len(addrs))
+	for i, addr := range addrs {
+		t := cloneTransport(base)
+		t.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			LocalAddr: addr,
+		}).DialContext
+		transports[i] = t
+	}
+	return rotate(transports)
+}
+
+func rotate(transports []http.RoundTripper) Selector {
+	return func(req *http.Request, attempt int) http.RoundTripper {
+		if len(transports) == 0 {
+			return nil
+		}
+		return transports[(attempt-1)%len(transports)]
+	}
+}
+
+func cloneTransport(base *http.Transport) *http.Transport {
+	if base == nil {
+		return http.DefaultTransport.(*http.Transport).Clone()
+	}
+	return base.Clone()
+}
@@ -0,0 +1,80 @@
+package retryhttp
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_TransportRotateProxies(t *testing.T) {
+
+	var hits []string
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, "blocked")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer blocked.Close()
+	open := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, "open")
+		if r.URL.Host != "example.invalid" {
+			t.Fatalf("Request not proxied, got %v", r.URL)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer open.Close()
+
+	blockedURL, _ := url.Parse(blocked.URL)
+	openURL, _ := url.Parse(open.URL)
+
+	transport := NewTransport(nil, newRetry(3, nil))
+	transport.Select = RotateProxies(nil, blockedURL, openURL)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://example.invalid/resource")
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("Response not expected, status: %d, body: %s", resp.StatusCode, body)
+	}
+	if len(hits) != 2 || hits[0] != "blocked" || hits[1] != "open" {
+		t.Fatalf("Proxies not rotated, got %v", hits)
+	}
+}
+
+func Test_RotateLocalAddrs(t *testing.T) {
+
+	if l, err := net.Listen("tcp", "127.0.0.2:0"); err != nil {
+		t.Skip("127.0.0.2 not available")
+	} else {
+		l.Close()
+	}
+
+	var remotes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		remotes = append(remotes, host)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, newRetry(1, nil))
+	transport.Select = RotateLocalAddrs(nil, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 2)})
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(remotes) != 2 || remotes[0] != "127.0.0.1" || remotes[1] != "127.0.0.2" {
+		t.Fatalf("Local addresses not rotated, got %v", remotes)
+	}
+}
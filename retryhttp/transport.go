@@ -0,0 +1,262 @@
+// Package retryhttp provides an http.RoundTripper that retries requests using a retry.Retry
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/nidorx/retry"
+)
+
+// DefaultMethods idempotent methods retried by default
+var DefaultMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+// DefaultStatusCodes response status codes retried by default
+var DefaultStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// maxDrain maximum number of bytes read from a discarded response body, allowing the connection to be reused
+const maxDrain = 4096
+
+// StatusError returned to the retry loop for a response with a retryable status code
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "retryhttp: unexpected status " + e.Status
+}
+
+// Transport An http.RoundTripper that retries requests with a retry.Retry, usable as a drop-in http.Client.Transport.
+//
+// Only idempotent methods are retried by default. Responses with a retryable status code, transient network errors
+// and attempts cut off by their own deadline (attempt timeout, watchdog) are retried. Request bodies are rewound with
+// Request.GetBody; a request with a body but no GetBody is sent once, without retries. The Retry-After response header
+// overrides the delay computed by the backoff strategy. When retries are exhausted on a retryable status, the last
+// response is returned as is.
+type Transport struct {
+	// Base the underlying RoundTripper, http.DefaultTransport if nil
+	Base http.RoundTripper
+	// Retry the retry policy
+	Retry *retry.Retry
+	// Methods the methods retried, DefaultMethods if nil
+	Methods []string
+	// StatusCodes the response status codes retried, DefaultStatusCodes if nil
+	StatusCodes []int
+	// Select optional, selects the RoundTripper of each attempt to rotate the egress path, see RotateProxies
+	Select Selector
+	// TracePhases whether the errors of the attempts are wrapped in an AttemptError with the timings of the DNS,
+	// connect, TLS and TTFB phases
+	TracePhases bool
+}
+
+// NewTransport initialize new Transport
+func NewTransport(base http.RoundTripper, r *retry.Retry) *Transport {
+	return &Transport{Base: base, Retry: r}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryMethod(req.Method) || !rewindable(req) {
+		return t.base().RoundTrip(req)
+	}
+
+	var last *http.Response
+	err := t.Retry.Execute(req.Context(), func(ctx context.Context, attempt int) error {
+		if last != nil {
+			discard(last)
+			last = nil
+		}
+
+		var trace *phaseTrace
+		if t.TracePhases {
+			ctx, trace = newPhaseTrace(ctx)
+		}
+		resp, err := t.attempt(ctx, req, attempt)
+		last = resp
+		if trace != nil {
+			return trace.wrap(err)
+		}
+		return err
+	})
+
+	if last != nil {
+		var retryErr *retry.Error
+		if err == nil || (errors.As(err, &retryErr) && (retryErr.Cause() == nil || errors.Is(retryErr.Cause(), retry.ErrMaxElapsedTime))) {
+			// retries exhausted on a retryable status, the caller gets the last response
+			return last, nil
+		}
+		discard(last)
+	}
+	return nil, err
+}
+
+// attempt sends the request once. The response is also returned along with the error of a retryable status.
+func (t *Transport) attempt(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+	attemptReq, err := rewind(req.WithContext(ctx), attempt)
+	if err != nil {
+		return nil, retry.Unrecoverable(err)
+	}
+
+	// the body is read after the attempt returns, its context must not be canceled before the body is closed
+	release := retry.HoldAttempt(ctx)
+	resp, err := t.roundTripper(attemptReq, attempt).RoundTrip(attemptReq)
+	if err != nil {
+		release()
+		if req.Context().Err() != nil {
+			// canceled by the caller
+			return nil, retry.Unrecoverable(err)
+		}
+		if ctx.Err() != nil || IsTransient(err) {
+			// the attempt context is done (attempt timeout, watchdog) or a transient network error
+			return nil, err
+		}
+		return nil, retry.Unrecoverable(err)
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+
+	if !t.retryStatus(resp.StatusCode) {
+		return resp, nil
+	}
+
+	statusErr := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	if delay, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		return resp, retry.RetryAfter(statusErr, delay)
+	}
+	return resp, statusErr
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// roundTripper returns the RoundTripper of the attempt
+func (t *Transport) roundTripper(req *http.Request, attempt int) http.RoundTripper {
+	if t.Select != nil {
+		if rt := t.Select(req, attempt); rt != nil {
+			return rt
+		}
+	}
+	return t.base()
+}
+
+func (t *Transport) retryMethod(method string) bool {
+	methods := t.Methods
+	if methods == nil {
+		methods = DefaultMethods
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Transport) retryStatus(code int) bool {
+	codes := t.StatusCodes
+	if codes == nil {
+		codes = DefaultStatusCodes
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// rewindable reports whether the body of the request can be sent again
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns the request for the attempt, with a fresh body from GetBody after the first attempt
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("retryhttp: cannot rewind body of %s %s, GetBody is nil", req.Method, req.URL)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// releaseBody releases the hold on the attempt context when the body is closed
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// discard drains and closes the response body, allowing the connection to be reused
+func discard(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrain)
+	_ = resp.Body.Close()
+}
+
+// IsTransient reports whether the error returned by a RoundTripper is a transient network error
+func IsTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, in delay-seconds or HTTP-date
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
@@ -0,0 +1,242 @@
+package retryhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nidorx/retry"
+)
+
+func newRetry(retries int, waits *[]time.Duration) *retry.Retry {
+	return retry.NewWithOptions(
+		retry.WithRetries(retries),
+		retry.WithFixedBackOff(time.Millisecond),
+		retry.WithWaiter(retry.WaiterFunc(func(ctx context.Context, d time.Duration) error {
+			if waits != nil {
+				*waits = append(*waits, d)
+			}
+			return nil
+		})),
+	)
+}
+
+func Test_TransportRetryAfter(t *testing.T) {
+
+	var calls int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: NewTransport(nil, newRetry(3, &waits))}
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("Response not expected, status: %d, body: %s", resp.StatusCode, body)
+	}
+
+	if len(waits) != 2 || waits[0] != 2*time.Second {
+		t.Fatalf("Waits not equal, want: %v, got %v", []time.Duration{2 * time.Second, 2 * time.Second}, waits)
+	}
+
+	for _, b := range bodies {
+		if b != "payload" {
+			t.Fatalf("Body not rewound, got %v", bodies)
+		}
+	}
+}
+
+func Test_TransportExhausted(t *testing.T) {
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, newRetry(2, nil))}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway || calls != 3 {
+		t.Fatalf("Response not expected, status: %d, calls: %d", resp.StatusCode, calls)
+	}
+}
+
+func Test_TransportNonIdempotent(t *testing.T) {
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, newRetry(3, nil))}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 1, calls)
+	}
+}
+
+func Test_TransportNetworkError(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: NewTransport(nil, newRetry(2, &waits))}
+
+	if _, err := client.Get(url); err == nil {
+		t.Fatalf("Error expected")
+	}
+
+	if len(waits) != 2 {
+		t.Fatalf("Count retries not equal, want: %d, got %d", 2, len(waits))
+	}
+}
+
+func Test_TransportStreamingBodyWithAttemptTimeout(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first,"))
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("second"))
+	}))
+	defer server.Close()
+
+	r := retry.NewWithOptions(
+		retry.WithRetries(1),
+		retry.WithAttemptTimeout(5*time.Second),
+		retry.WithWatchdog(time.Minute, func(ctx context.Context, attempt int, running time.Duration, cancel func()) {}),
+	)
+	client := &http.Client{Transport: NewTransport(nil, r)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Error not expected reading the body: %v", err)
+	}
+	if string(body) != "first,second" {
+		t.Fatalf("Body not equal, want: %s, got %s", "first,second", body)
+	}
+}
+
+func Test_TransportAttemptTimeout(t *testing.T) {
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	r := retry.NewWithOptions(
+		retry.WithRetries(3),
+		retry.WithFixedBackOff(time.Millisecond),
+		retry.WithAttemptTimeout(50*time.Millisecond),
+	)
+	client := &http.Client{Transport: NewTransport(nil, r)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+		t.Fatalf("Body not equal, want: %s, got %s", "ok", body)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 2, n)
+	}
+}
+
+func Test_TransportBodyWithoutGetBody(t *testing.T) {
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, newRetry(3, nil))}
+
+	// a body that http.NewRequest doesn't know how to rewind
+	req, _ := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("payload")))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Status not equal, want: %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 1, n)
+	}
+}
+
+func Test_ParseRetryAfter(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if d, ok := ParseRetryAfter("120", now); !ok || d != 2*time.Minute {
+		t.Fatalf("Delay not equal, want: %s, got %s", 2*time.Minute, d)
+	}
+
+	if d, ok := ParseRetryAfter("Mon, 01 Jan 2024 00:00:30 GMT", now); !ok || d != 30*time.Second {
+		t.Fatalf("Delay not equal, want: %s, got %s", 30*time.Second, d)
+	}
+
+	if _, ok := ParseRetryAfter("soon", now); ok {
+		t.Fatalf("Invalid value should not be parsed")
+	}
+}
@@ -0,0 +1,131 @@
+// Package retrytest provides utilities for testing code built on the retry package.
+package retrytest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock A fake retry.Clock. Time only moves when Advance is called or, for a clock created with NewAutoClock, when
+// a wait starts. Every wait is recorded, so tests can assert on the sequence of computed delays.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	auto    bool
+	delays  []time.Duration
+	waiters []*waiter
+	changed chan struct{}
+}
+
+type waiter struct {
+	deadline time.Time
+	done     chan struct{}
+}
+
+// NewClock creates a fake clock at the given time. Waits block until the clock is advanced past their deadline.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now, changed: make(chan struct{})}
+}
+
+// NewAutoClock creates a fake clock at the given time that advances by the duration of each wait, which returns
+// immediately. Useful to run a whole schedule in a single call to Execute.
+func NewAutoClock(now time.Time) *Clock {
+	c := NewClock(now)
+	c.auto = true
+	return c
+}
+
+// Now returns the current time of the clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Wait blocks until the clock is advanced by d or the context is done, returning ctx.Err() in the latter case
+func (c *Clock) Wait(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	c.delays = append(c.delays, d)
+	if c.auto {
+		c.now = c.now.Add(d)
+		c.mu.Unlock()
+		return nil
+	}
+	if d <= 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	w := &waiter{deadline: c.now.Add(d), done: make(chan struct{})}
+	c.waiters = append(c.waiters, w)
+	c.notify()
+	c.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		c.remove(w)
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Advance moves the clock forward, releasing the waits whose deadline was reached
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if c.now.Before(w.deadline) {
+			pending = append(pending, w)
+		} else {
+			close(w.done)
+		}
+	}
+	c.waiters = pending
+	c.notify()
+}
+
+// BlockUntil blocks until n waits are pending or the context is done. Use it to synchronize with an Execute running in
+// another goroutine before calling Advance.
+func (c *Clock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		pending, changed := len(c.waiters), c.changed
+		c.mu.Unlock()
+		if pending >= n {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Delays returns the durations of all the waits started so far, in order
+func (c *Clock) Delays() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.delays...)
+}
+
+func (c *Clock) remove(w *waiter) {
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	c.notify()
+}
+
+// notify wakes up the BlockUntil calls, must be called with the lock held
+func (c *Clock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
@@ -0,0 +1,170 @@
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nidorx/retry"
+)
+
+var errFail = errors.New("fail")
+
+func Test_AutoClock(t *testing.T) {
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewAutoClock(start)
+	retries := retry.NewWithOptions(
+		retry.WithRetries(5),
+		retry.WithExponentialBackoff(time.Second, time.Hour, 10),
+		retry.WithClock(clock),
+	)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return errFail
+	})
+
+	var retryErr *retry.Error
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+
+	want := []time.Duration{time.Second, 10 * time.Second, 100 * time.Second, 1000 * time.Second, time.Hour}
+	delays := clock.Delays()
+	if len(delays) != len(want) {
+		t.Fatalf("Delays not equal, want: %v, got %v", want, delays)
+	}
+	var total time.Duration
+	for i, d := range want {
+		if delays[i] != d {
+			t.Fatalf("Delay not equal, want: %v, got %v", d, delays[i])
+		}
+		total += d
+	}
+	if retryErr.Elapsed() != total {
+		t.Fatalf("Elapsed not equal, want: %v, got %v", total, retryErr.Elapsed())
+	}
+	if !clock.Now().Equal(start.Add(total)) {
+		t.Fatalf("Now not equal, want: %v, got %v", start.Add(total), clock.Now())
+	}
+}
+
+func Test_AutoClockMaxElapsedTime(t *testing.T) {
+
+	clock := NewAutoClock(time.Now())
+	retries := retry.NewWithOptions(
+		retry.WithRetries(-1),
+		retry.WithFixedBackOff(time.Minute),
+		retry.WithMaxElapsedTime(time.Hour),
+		retry.WithClock(clock),
+	)
+
+	attempts := 0
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		attempts++
+		return errFail
+	})
+	if !errors.Is(err, retry.ErrMaxElapsedTime) {
+		t.Fatalf("Error not equal, want: %v, got %v", retry.ErrMaxElapsedTime, err)
+	}
+	if attempts != 61 {
+		t.Fatalf("Attempts not equal, want: %d, got %d", 61, attempts)
+	}
+}
+
+func Test_ClockAdvance(t *testing.T) {
+
+	clock := NewClock(time.Now())
+	retries := retry.NewWithOptions(
+		retry.WithRetries(2),
+		retry.WithFixedBackOff(time.Minute),
+		retry.WithClock(clock),
+	)
+
+	done := make(chan error)
+	go func() {
+		done <- retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+			if attempt < 3 {
+				return errFail
+			}
+			return nil
+		})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if err := clock.BlockUntil(ctx, 1); err != nil {
+			t.Fatalf("Wait not started")
+		}
+		clock.Advance(30 * time.Second)
+		select {
+		case <-done:
+			t.Fatalf("Wait released before its deadline")
+		default:
+		}
+		clock.Advance(30 * time.Second)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+}
+
+func Test_ClockCancel(t *testing.T) {
+
+	clock := NewClock(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := clock.Wait(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error not equal, want: %v, got %v", context.Canceled, err)
+	}
+	if err := clock.BlockUntil(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error not equal, want: %v, got %v", context.Canceled, err)
+	}
+}
+
+func Test_ClockHedgeDelay(t *testing.T) {
+
+	clock := NewClock(time.Now())
+	retries := retry.NewWithOptions(
+		retry.WithHedgeDelay(time.Hour),
+		retry.WithMaxHedges(1),
+		retry.WithClock(clock),
+	)
+
+	var launched atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- retries.ExecuteHedged(context.Background(), func(ctx context.Context, attempt int) error {
+			launched.Add(1)
+			if attempt == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := clock.BlockUntil(ctx, 1); err != nil {
+		t.Fatalf("Hedge delay not waited on the clock: %v", err)
+	}
+	if n := launched.Load(); n > 1 {
+		t.Fatalf("Hedge launched before the clock advanced, attempts: %d", n)
+	}
+
+	clock.Advance(time.Hour)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Error not expected: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Hedge not launched after the clock advanced")
+	}
+}
@@ -0,0 +1,22 @@
+package retry
+
+import "math/rand"
+
+// WithSampling records the telemetry of only a fraction of the successful executions, keeping observability of hot
+// operations affordable without losing failure visibility. For an execution not sampled, the OnAttempt hook and the
+// Tracer skip the first attempt, and the OnSuccess hook is skipped if it succeeds. Failures are always recorded:
+// OnError, OnGiveUp and every attempt after a failure. Stats are not sampled.
+// rate - fraction of the executions sampled, between 0 and 1
+func WithSampling(rate float64) Option {
+	return func(r *Retry) {
+		r.sampling = rate
+	}
+}
+
+// sample decides whether the telemetry of an execution is recorded
+func (r *Retry) sample() bool {
+	if r.sampling >= 1 {
+		return true
+	}
+	return r.sampling > 0 && rand.Float64() < r.sampling
+}
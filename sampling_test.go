@@ -0,0 +1,84 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Sampling(t *testing.T) {
+
+	var attempts, spans, successes, giveUps, failures int
+	retries := NewWithOptions(
+		WithRetries(1),
+		WithFixedBackOff(time.Millisecond),
+		WithSampling(0),
+		WithOnAttempt(func(ctx context.Context, attempt int) {
+			attempts++
+		}),
+		WithTracer(TracerFunc(func(ctx context.Context, attempt int) (context.Context, func(err error)) {
+			spans++
+			return ctx, func(err error) {}
+		})),
+		WithOnSuccess(func(ctx context.Context, attempts int, elapsed time.Duration) {
+			successes++
+		}),
+		WithOnGiveUp(func(ctx context.Context, err error, attempts int, elapsed time.Duration) {
+			giveUps++
+		}),
+		WithOnError(func(ctx context.Context, err error, attempt int, willRetry bool, nextRetry time.Duration) {
+			failures++
+		}),
+	)
+
+	// successes at the first attempt are not recorded
+	for i := 0; i < 10; i++ {
+		_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+			return nil
+		})
+	}
+	if attempts != 0 || spans != 0 || successes != 0 {
+		t.Fatalf("Telemetry not expected, attempts: %d, spans: %d, successes: %d", attempts, spans, successes)
+	}
+
+	// attempts after a failure are recorded
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+	if attempts != 1 || spans != 1 || successes != 1 || failures != 1 {
+		t.Fatalf("Telemetry not expected, attempts: %d, spans: %d, successes: %d, failures: %d", attempts, spans, successes, failures)
+	}
+
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	})
+	if giveUps != 1 || failures != 3 {
+		t.Fatalf("Telemetry not expected, give-ups: %d, failures: %d", giveUps, failures)
+	}
+
+	if stats := retries.Stats(); stats.Executions != 12 || stats.Successes != 11 {
+		t.Fatalf("Stats not expected, got %+v", stats)
+	}
+}
+
+func Test_SamplingRate(t *testing.T) {
+
+	if !New(0, nil).sample() {
+		t.Fatalf("Executions not sampled by default")
+	}
+
+	retries := NewWithOptions(WithSampling(0.5))
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		if retries.sample() {
+			sampled++
+		}
+	}
+	if sampled < 400 || sampled > 600 {
+		t.Fatalf("Sampled not expected, got %d", sampled)
+	}
+}
@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrSemaphoreTimeout = errors.New("retry: timeout acquiring semaphore")
+
+// Semaphore A weighted semaphore keyed by operation type, controlling the write amplification of retries on a
+// downstream dependency. Retry attempts wait for a shorter time than first attempts, so retries never crowd out
+// fresh traffic to the same dependency.
+type Semaphore struct {
+	mu           sync.Mutex
+	capacity     int64
+	firstTimeout time.Duration
+	retryTimeout time.Duration
+	used         map[string]int64
+	released     chan struct{}
+}
+
+// NewSemaphore initialize new Semaphore
+// capacity - maximum weight held at any time for each key
+// firstTimeout - maximum time a first attempt waits to acquire
+// retryTimeout - maximum time a retry attempt waits to acquire, should be shorter than firstTimeout
+func NewSemaphore(capacity int64, firstTimeout time.Duration, retryTimeout time.Duration) *Semaphore {
+	return &Semaphore{
+		capacity:     capacity,
+		firstTimeout: firstTimeout,
+		retryTimeout: retryTimeout,
+		used:         map[string]int64{},
+		released:     make(chan struct{}),
+	}
+}
+
+// Acquire acquires the given weight for key, waiting up to the timeout relative to the attempt number
+func (s *Semaphore) Acquire(ctx context.Context, key string, weight int64, attempt int) error {
+	timeout := s.firstTimeout
+	if attempt > 1 {
+		timeout = s.retryTimeout
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	for {
+		s.mu.Lock()
+		if s.used[key]+weight <= s.capacity {
+			s.used[key] += weight
+			s.mu.Unlock()
+			return nil
+		}
+		released := s.released
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			return ErrSemaphoreTimeout
+		case <-released:
+		}
+	}
+}
+
+// Release releases the given weight for key
+func (s *Semaphore) Release(key string, weight int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.used[key] -= weight
+	if s.used[key] <= 0 {
+		delete(s.used, key)
+	}
+
+	// wake up all waiters
+	close(s.released)
+	s.released = make(chan struct{})
+}
+
+// Wrap returns a callback that holds the semaphore during each attempt
+func (s *Semaphore) Wrap(key string, weight int64, callback func(ctx context.Context, attempt int) error) func(ctx context.Context, attempt int) error {
+	return func(ctx context.Context, attempt int) error {
+		if err := s.Acquire(ctx, key, weight, attempt); err != nil {
+			return err
+		}
+		defer s.Release(key, weight)
+		return callback(ctx, attempt)
+	}
+}
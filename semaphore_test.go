@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Semaphore(t *testing.T) {
+
+	sem := NewSemaphore(2, 50*time.Millisecond, time.Millisecond)
+	ctx := context.Background()
+
+	if err := sem.Acquire(ctx, "write", 2, 1); err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	// other keys are independent
+	if err := sem.Acquire(ctx, "read", 1, 1); err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	// retries give up quickly
+	if err := sem.Acquire(ctx, "write", 1, 2); !errors.Is(err, ErrSemaphoreTimeout) {
+		t.Fatalf("ErrSemaphoreTimeout expected, got %v", err)
+	}
+
+	// first attempts wait for release
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		sem.Release("write", 2)
+	}()
+	if err := sem.Acquire(ctx, "write", 1, 1); err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+}
+
+func Test_SemaphoreWrap(t *testing.T) {
+
+	sem := NewSemaphore(1, time.Millisecond, time.Millisecond)
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.Execute(context.Background(), sem.Wrap("write", 1, executeFn))
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	if len(sem.used) != 0 {
+		t.Fatalf("Semaphore not released, got %v", sem.used)
+	}
+}
@@ -0,0 +1,35 @@
+package retry
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// OnSoftLimit is invoked once per execution when the time it will have spent by its next attempt crosses a soft
+// threshold (fraction) of the max elapsed time, giving early warning before retries start being dropped
+type OnSoftLimit func(ctx context.Context, threshold float64, elapsed time.Duration, maxElapsed time.Duration)
+
+// WithSoftLimits emits an event when an execution crosses each of the thresholds, fractions of the max elapsed time
+// such as 0.8. Has no effect without a max elapsed time.
+func WithSoftLimits(onSoftLimit OnSoftLimit, thresholds ...float64) Option {
+	sorted := append([]float64(nil), thresholds...)
+	sort.Float64s(sorted)
+	return func(r *Retry) {
+		r.onSoftLimit = onSoftLimit
+		r.softLimits = sorted
+	}
+}
+
+// softLimit emits the thresholds crossed by the elapsed time, skipping the first emitted ones. Returns the number of
+// thresholds emitted so far.
+func (r *Retry) softLimit(ctx context.Context, emitted int, elapsed time.Duration) int {
+	if r.onSoftLimit == nil || r.maxElapsed <= 0 {
+		return emitted
+	}
+	for emitted < len(r.softLimits) && float64(elapsed) >= r.softLimits[emitted]*float64(r.maxElapsed) {
+		r.onSoftLimit(ctx, r.softLimits[emitted], elapsed, r.maxElapsed)
+		emitted++
+	}
+	return emitted
+}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// autoClock a Clock that advances by the duration of each wait
+type autoClock struct {
+	now time.Time
+}
+
+func (c *autoClock) Now() time.Time {
+	return c.now
+}
+
+func (c *autoClock) Wait(ctx context.Context, d time.Duration) error {
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func Test_SoftLimits(t *testing.T) {
+
+	var thresholds []float64
+	var elapsed []time.Duration
+	retries := NewWithOptions(
+		WithRetries(-1),
+		WithFixedBackOff(10*time.Second),
+		WithMaxElapsedTime(100*time.Second),
+		WithClock(&autoClock{now: time.Now()}),
+		WithSoftLimits(func(ctx context.Context, threshold float64, e time.Duration, maxElapsed time.Duration) {
+			thresholds = append(thresholds, threshold)
+			elapsed = append(elapsed, e)
+		}, 0.8, 0.5),
+	)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	})
+	if !errors.Is(err, ErrMaxElapsedTime) {
+		t.Fatalf("Error not equal, want: %v, got %v", ErrMaxElapsedTime, err)
+	}
+
+	if len(thresholds) != 2 || thresholds[0] != 0.5 || thresholds[1] != 0.8 {
+		t.Fatalf("Thresholds not expected, got %v", thresholds)
+	}
+	if elapsed[0] != 50*time.Second || elapsed[1] != 80*time.Second {
+		t.Fatalf("Elapsed not expected, got %v", elapsed)
+	}
+}
+
+func Test_SoftLimitsWithoutMaxElapsed(t *testing.T) {
+
+	retries := NewWithOptions(
+		WithRetries(3),
+		WithFixedBackOff(time.Millisecond),
+		WithSoftLimits(func(ctx context.Context, threshold float64, elapsed time.Duration, maxElapsed time.Duration) {
+			t.Fatalf("Soft limit not expected")
+		}, 0),
+	)
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return errors.New("fail")
+	})
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"errors"
+	"math"
+)
+
+// Limits imposed by Amazon SQS, in seconds.
+const (
+	SQSMaxVisibilityTimeout = 43200 // 12 hours
+	SQSMaxDelaySeconds      = 900   // 15 minutes
+)
+
+var ErrUnlimitedRetries = errors.New("retry: unlimited retries cannot be expressed as a finite schedule")
+
+// SQSSchedule Per-attempt visibility timeout and delay values derived from a BackoffStrategy, suitable for SQS
+// redrive configuration. Values are in seconds, rounded up and clamped to the SQS limits.
+type SQSSchedule struct {
+	MaxReceiveCount   int   // maxReceiveCount of the redrive policy (first attempt + retries)
+	VisibilityTimeout []int // VisibilityTimeout[i] is the timeout to set after the (i+1)th failed receive
+	DelaySeconds      []int // DelaySeconds[i] is the delay to use when re-enqueuing after the (i+1)th failure
+}
+
+// NewSQSSchedule converts a backoff strategy and the number of retries into an SQSSchedule
+func NewSQSSchedule(strategy BackoffStrategy, numberOfRetries int) (*SQSSchedule, error) {
+	if numberOfRetries < 0 {
+		return nil, ErrUnlimitedRetries
+	}
+
+	s := &SQSSchedule{
+		MaxReceiveCount:   numberOfRetries + 1,
+		VisibilityTimeout: make([]int, numberOfRetries),
+		DelaySeconds:      make([]int, numberOfRetries),
+	}
+	for i := 0; i < numberOfRetries; i++ {
+		seconds := int(math.Ceil(strategy.NextDelay(i+1, nil).Seconds()))
+		s.VisibilityTimeout[i] = clamp(seconds, 0, SQSMaxVisibilityTimeout)
+		s.DelaySeconds[i] = clamp(seconds, 0, SQSMaxDelaySeconds)
+	}
+	return s, nil
+}
+
+// VisibilityTimeoutFor returns the visibility timeout for a message with the given ApproximateReceiveCount. Returns 0
+// once the message has exhausted its retries.
+func (s *SQSSchedule) VisibilityTimeoutFor(receiveCount int) int {
+	if receiveCount < 1 || receiveCount > len(s.VisibilityTimeout) {
+		return 0
+	}
+	return s.VisibilityTimeout[receiveCount-1]
+}
+
+// SQSSchedule returns the SQSSchedule equivalent to the current configuration of this Retry
+func (r *Retry) SQSSchedule() (*SQSSchedule, error) {
+	if r.unlimited {
+		return nil, ErrUnlimitedRetries
+	}
+	return NewSQSSchedule(r.backoffStrategy(), r.retries)
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
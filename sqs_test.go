@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_SQSSchedule(t *testing.T) {
+
+	retries := New(5, nil)
+	retries.SetExponentialBackoff(1500, 1200000, 10)
+
+	schedule, err := retries.SQSSchedule()
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	if schedule.MaxReceiveCount != 6 {
+		t.Fatalf("MaxReceiveCount not equal, want: %d, got %d", 6, schedule.MaxReceiveCount)
+	}
+
+	// 1.5s, 15s, 150s, 1200s, 1200s
+	wantVisibility := []int{2, 15, 150, 1200, 1200}
+	wantDelay := []int{2, 15, 150, 900, 900}
+	for i := range wantVisibility {
+		if schedule.VisibilityTimeout[i] != wantVisibility[i] {
+			t.Fatalf("VisibilityTimeout[%d] not equal, want: %d, got %d", i, wantVisibility[i], schedule.VisibilityTimeout[i])
+		}
+		if schedule.DelaySeconds[i] != wantDelay[i] {
+			t.Fatalf("DelaySeconds[%d] not equal, want: %d, got %d", i, wantDelay[i], schedule.DelaySeconds[i])
+		}
+	}
+
+	if v := schedule.VisibilityTimeoutFor(2); v != 15 {
+		t.Fatalf("VisibilityTimeoutFor(2) not equal, want: %d, got %d", 15, v)
+	}
+
+	if v := schedule.VisibilityTimeoutFor(6); v != 0 {
+		t.Fatalf("VisibilityTimeoutFor(6) not equal, want: %d, got %d", 0, v)
+	}
+}
+
+func Test_SQSScheduleUnlimited(t *testing.T) {
+	_, err := New(-1, nil).SQSSchedule()
+	if !errors.Is(err, ErrUnlimitedRetries) {
+		t.Fatalf("ErrUnlimitedRetries expected, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// OnAttempt is invoked before each attempt
+type OnAttempt func(ctx context.Context, attempt int)
+
+// OnSuccess is invoked when the callback succeeds
+type OnSuccess func(ctx context.Context, attempts int, elapsed time.Duration)
+
+// OnGiveUp is invoked when the execution gives up, with the error returned by Execute
+type OnGiveUp func(ctx context.Context, err error, attempts int, elapsed time.Duration)
+
+// Tracer creates a span per attempt, with a shape compatible with OpenTelemetry without depending on it. StartAttempt
+// returns the context for the attempt and a function to end the span with the attempt error (nil on success).
+type Tracer interface {
+	StartAttempt(ctx context.Context, attempt int) (context.Context, func(err error))
+}
+
+// TracerFunc An adapter to allow the use of ordinary functions as Tracer
+type TracerFunc func(ctx context.Context, attempt int) (context.Context, func(err error))
+
+func (f TracerFunc) StartAttempt(ctx context.Context, attempt int) (context.Context, func(err error)) {
+	return f(ctx, attempt)
+}
+
+// Stats A snapshot of the statistics of a Retry, suitable for exporting to Prometheus or expvar
+type Stats struct {
+	Executions int64         // calls to Execute
+	Attempts   int64         // callback invocations
+	Retries    int64         // attempts scheduled after a failure
+	Successes  int64         // executions that succeeded
+	Failures   int64         // executions that gave up
+	Backoff    time.Duration // cumulative backoff time scheduled
+	Warnings   int64         // warnings reported with Warn
+}
+
+type counters struct {
+	executions atomic.Int64
+	attempts   atomic.Int64
+	retries    atomic.Int64
+	successes  atomic.Int64
+	failures   atomic.Int64
+	backoff    atomic.Int64
+	warnings   atomic.Int64
+	costs      costs
+}
+
+// SetOnAttempt Set the hook invoked before each attempt
+//
+// Deprecated: use NewWithOptions with WithOnAttempt.
+func (r *Retry) SetOnAttempt(onAttempt OnAttempt) {
+	r.apply(WithOnAttempt(onAttempt))
+}
+
+// SetOnSuccess Set the hook invoked when the callback succeeds
+//
+// Deprecated: use NewWithOptions with WithOnSuccess.
+func (r *Retry) SetOnSuccess(onSuccess OnSuccess) {
+	r.apply(WithOnSuccess(onSuccess))
+}
+
+// SetOnGiveUp Set the hook invoked when the execution gives up
+//
+// Deprecated: use NewWithOptions with WithOnGiveUp.
+func (r *Retry) SetOnGiveUp(onGiveUp OnGiveUp) {
+	r.apply(WithOnGiveUp(onGiveUp))
+}
+
+// SetTracer Set the tracer used to create a span per attempt
+//
+// Deprecated: use NewWithOptions with WithTracer.
+func (r *Retry) SetTracer(tracer Tracer) {
+	r.apply(WithTracer(tracer))
+}
+
+// Stats returns a snapshot of the statistics of this Retry. Safe for concurrent use.
+func (r *Retry) Stats() Stats {
+	if r.counters == nil {
+		return Stats{}
+	}
+	return Stats{
+		Executions: r.counters.executions.Load(),
+		Attempts:   r.counters.attempts.Load(),
+		Retries:    r.counters.retries.Load(),
+		Successes:  r.counters.successes.Load(),
+		Failures:   r.counters.failures.Load(),
+		Backoff:    time.Duration(r.counters.backoff.Load()),
+		Warnings:   r.counters.warnings.Load(),
+	}
+}
@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Hooks(t *testing.T) {
+
+	var events []string
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetOnAttempt(func(ctx context.Context, attempt int) {
+		events = append(events, "attempt")
+	})
+	retries.SetOnSuccess(func(ctx context.Context, attempts int, elapsed time.Duration) {
+		events = append(events, "success")
+		if attempts != 4 {
+			t.Fatalf("Attempts not equal, want: %d, got %d", 4, attempts)
+		}
+	})
+	retries.SetOnGiveUp(func(ctx context.Context, err error, attempts int, elapsed time.Duration) {
+		events = append(events, "give-up")
+	})
+	retries.SetTracer(TracerFunc(func(ctx context.Context, attempt int) (context.Context, func(err error)) {
+		events = append(events, "span-start")
+		return ctx, func(err error) {
+			events = append(events, "span-end")
+		}
+	}))
+
+	if err := retries.Execute(context.Background(), executeFn); err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if len(events) != 13 || events[0] != "attempt" || events[1] != "span-start" || events[2] != "span-end" || events[12] != "success" {
+		t.Fatalf("Events not expected, got %v", events)
+	}
+
+	events = nil
+	_ = retries.Execute(NoRetryContext(context.Background()), executeFn)
+	if len(events) != 4 || events[3] != "give-up" {
+		t.Fatalf("Give up expected, got %v", events)
+	}
+}
+
+func Test_Stats(t *testing.T) {
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = retries.Execute(context.Background(), executeFn)
+		}()
+	}
+	wg.Wait()
+
+	_ = retries.Execute(NoRetryContext(context.Background()), executeFn)
+
+	stats := retries.Stats()
+	want := Stats{
+		Executions: 11,
+		Attempts:   41,
+		Retries:    30,
+		Successes:  10,
+		Failures:   1,
+		Backoff:    30 * time.Millisecond,
+	}
+	if stats != want {
+		t.Fatalf("Stats not equal, want: %+v, got %+v", want, stats)
+	}
+}
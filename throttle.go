@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var ErrThrottled = errors.New("retry: attempt rejected by client-side throttling")
+
+const throttleBuckets = 10
+
+// AdaptiveThrottle Client-side adaptive throttling (Google SRE), an alternative to a binary circuit breaker. Attempts
+// are rejected locally with probability max(0, (requests - K * accepts) / (requests + 1)), computed over a sliding
+// window, so the client sheds load smoothly as the backend starts failing.
+type AdaptiveThrottle struct {
+	mu       sync.Mutex
+	k        float64
+	bucket   time.Duration
+	requests [throttleBuckets]float64
+	accepts  [throttleBuckets]float64
+	current  int
+	updated  time.Time
+	rand     func() float64
+	now      func() time.Time
+}
+
+// NewAdaptiveThrottle initialize new AdaptiveThrottle
+// k - multiplier of accepts, lower values throttle more aggressively (2 is a common choice)
+// window - period over which requests and accepts are counted, at least 10ns
+func NewAdaptiveThrottle(k float64, window time.Duration) *AdaptiveThrottle {
+	if window < throttleBuckets {
+		window = throttleBuckets
+	}
+	return &AdaptiveThrottle{
+		k:      k,
+		bucket: window / throttleBuckets,
+		rand:   rand.Float64,
+		now:    time.Now,
+	}
+}
+
+// SetRand Set the random source, returning values in [0, 1). Allows deterministic tests.
+func (t *AdaptiveThrottle) SetRand(rand func() float64) {
+	t.rand = rand
+}
+
+// Allow reports whether an attempt may be sent. A rejected attempt is counted as a request.
+func (t *AdaptiveThrottle) Allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance()
+
+	if t.rand() < t.rejectProbability() {
+		t.requests[t.current]++
+		return false
+	}
+	return true
+}
+
+// Record records the outcome of an allowed attempt
+func (t *AdaptiveThrottle) Record(accepted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance()
+
+	t.requests[t.current]++
+	if accepted {
+		t.accepts[t.current]++
+	}
+}
+
+// RejectProbability returns the current probability of rejecting an attempt
+func (t *AdaptiveThrottle) RejectProbability() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance()
+	return t.rejectProbability()
+}
+
+func (t *AdaptiveThrottle) rejectProbability() float64 {
+	requests, accepts := 0.0, 0.0
+	for i := 0; i < throttleBuckets; i++ {
+		requests += t.requests[i]
+		accepts += t.accepts[i]
+	}
+	return math.Max(0, (requests-t.k*accepts)/(requests+1))
+}
+
+// advance moves the window, clearing expired buckets
+func (t *AdaptiveThrottle) advance() {
+	now := t.now()
+	if t.updated.IsZero() {
+		t.updated = now
+		return
+	}
+	elapsed := int(now.Sub(t.updated) / t.bucket)
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > throttleBuckets {
+		elapsed = throttleBuckets
+	}
+	for i := 0; i < elapsed; i++ {
+		t.current = (t.current + 1) % throttleBuckets
+		t.requests[t.current] = 0
+		t.accepts[t.current] = 0
+	}
+	t.updated = now
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_AdaptiveThrottle(t *testing.T) {
+
+	now := time.Now()
+	throttle := NewAdaptiveThrottle(2, 10*time.Second)
+	throttle.now = func() time.Time { return now }
+	throttle.SetRand(func() float64 { return 0.5 })
+
+	for i := 0; i < 10; i++ {
+		throttle.Record(true)
+	}
+	if p := throttle.RejectProbability(); p != 0 {
+		t.Fatalf("Reject probability not equal, want: %f, got %f", 0.0, p)
+	}
+
+	// 10 accepts, 50 requests: (50 - 20) / 51
+	for i := 0; i < 40; i++ {
+		throttle.Record(false)
+	}
+	if p := throttle.RejectProbability(); p < 0.58 || p > 0.59 {
+		t.Fatalf("Reject probability not expected, got %f", p)
+	}
+	if throttle.Allow() {
+		t.Fatalf("Attempt should be rejected")
+	}
+
+	// window expired
+	now = now.Add(11 * time.Second)
+	if !throttle.Allow() {
+		t.Fatalf("Attempt should be allowed")
+	}
+}
+
+func Test_AdaptiveThrottleSmallWindow(t *testing.T) {
+
+	for _, window := range []time.Duration{-time.Second, 0, 5} {
+		throttle := NewAdaptiveThrottle(2, window)
+		throttle.Record(false)
+		throttle.Allow()
+		if p := throttle.RejectProbability(); p < 0 || p > 1 {
+			t.Fatalf("Reject probability out of range with window %s, got %f", window, p)
+		}
+	}
+}
+
+func Test_AdaptiveThrottleRetry(t *testing.T) {
+
+	throttle := NewAdaptiveThrottle(1, time.Minute)
+	throttle.SetRand(func() float64 { return 0.1 })
+
+	retries := New(10, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetThrottle(throttle)
+
+	calls := 0
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		calls++
+		return customErr
+	})
+
+	if !errors.Is(err, ErrThrottled) {
+		t.Fatalf("Error not equal, want: %v, got %v", ErrThrottled, err)
+	}
+
+	// 1 request: 1/2 >= 0.1
+	if calls != 1 {
+		t.Fatalf("Count calls not equal, want: %d, got %d", 1, calls)
+	}
+}
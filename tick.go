@@ -0,0 +1,108 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TickBackoffStrategy Rounds the delays of a strategy up to whole logical ticks, so deterministic simulations and game
+// servers can use the same policies as wall-clock services, with delays that are exact tick counts
+type TickBackoffStrategy struct {
+	strategy BackoffStrategy
+	tick     time.Duration
+}
+
+// NewTickBackoff wraps the strategy, rounding its delays up to multiples of tick
+func NewTickBackoff(strategy BackoffStrategy, tick time.Duration) *TickBackoffStrategy {
+	return &TickBackoffStrategy{strategy: strategy, tick: tick}
+}
+
+func (b *TickBackoffStrategy) NextDelay(attempt int, err error) time.Duration {
+	return time.Duration(toTicks(b.strategy.NextDelay(attempt, err), b.tick)) * b.tick
+}
+
+// TickClock A Clock driven by logical ticks advanced by the embedder (game loop, simulation step) instead of wall
+// time. Each tick represents the given duration; a wait lasts the number of ticks needed to cover its duration,
+// rounded up. Use with WithClock, running Execute outside the loop that calls Tick. Safe for concurrent use.
+type TickClock struct {
+	mu      sync.Mutex
+	tick    time.Duration
+	ticks   int64
+	waiters []tickWaiter
+}
+
+type tickWaiter struct {
+	due  int64
+	done chan struct{}
+}
+
+// NewTickClock initialize new TickClock
+func NewTickClock(tick time.Duration) *TickClock {
+	return &TickClock{tick: tick}
+}
+
+// Tick advances the clock by one tick, releasing the waits that are due
+func (c *TickClock) Tick() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ticks++
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.due <= c.ticks {
+			close(w.done)
+		} else {
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+}
+
+// Ticks returns the number of ticks elapsed
+func (c *TickClock) Ticks() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ticks
+}
+
+// Now returns the logical time, the ticks elapsed since the Unix epoch
+func (c *TickClock) Now() time.Time {
+	return time.Unix(0, 0).Add(time.Duration(c.Ticks()) * c.tick)
+}
+
+// Wait blocks until enough ticks elapse to cover the duration or the context is done, returning ctx.Err() in the
+// latter case
+func (c *TickClock) Wait(ctx context.Context, d time.Duration) error {
+	ticks := toTicks(d, c.tick)
+	if ticks <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	w := tickWaiter{due: c.ticks + ticks, done: make(chan struct{})}
+	c.waiters = append(c.waiters, w)
+	c.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		for i, other := range c.waiters {
+			if other.done == w.done {
+				c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+				break
+			}
+		}
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// toTicks the number of ticks needed to cover the duration, rounded up
+func toTicks(d time.Duration, tick time.Duration) int64 {
+	if d <= 0 || tick <= 0 {
+		return 0
+	}
+	return int64((d + tick - 1) / tick)
+}
@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_TickBackoff(t *testing.T) {
+
+	strategy := NewTickBackoff(NewExponentialBackoff(10*time.Millisecond, time.Second, 2), 16*time.Millisecond)
+	want := []time.Duration{16 * time.Millisecond, 32 * time.Millisecond, 48 * time.Millisecond, 80 * time.Millisecond}
+	for i, d := range want {
+		if got := strategy.NextDelay(i+1, nil); got != d {
+			t.Fatalf("Delay not equal, want: %v, got %v", d, got)
+		}
+	}
+}
+
+func Test_TickClock(t *testing.T) {
+
+	clock := NewTickClock(100 * time.Millisecond)
+	retries := NewWithOptions(
+		WithRetries(2),
+		WithFixedBackOff(250*time.Millisecond),
+		WithClock(clock),
+	)
+
+	done := make(chan error)
+	go func() {
+		done <- retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+			if attempt < 3 {
+				return errors.New("fail")
+			}
+			return nil
+		})
+	}()
+
+	// 250ms waits last 3 ticks each, the game loop keeps ticking until the execution finishes
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Error not expected, got %v", err)
+			}
+			if ticks := clock.Ticks(); ticks < 6 {
+				t.Fatalf("Ticks not expected, got %d", ticks)
+			}
+			return
+		case <-time.After(time.Millisecond):
+			clock.Tick()
+		}
+	}
+}
+
+func Test_TickClockCancel(t *testing.T) {
+
+	clock := NewTickClock(time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := clock.Wait(ctx, time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Error not equal, want: %v, got %v", context.Canceled, err)
+	}
+	if err := clock.Wait(ctx, 0); err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if !clock.Now().Equal(time.Unix(0, 0)) {
+		t.Fatalf("Now not expected, got %v", clock.Now())
+	}
+}
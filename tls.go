@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"strings"
+)
+
+// remote TLS alerts that may be caused by an in-progress certificate rotation
+var tlsRotationAlerts = []string{
+	"tls: bad certificate",
+	"tls: unknown certificate authority",
+	"tls: certificate expired",
+	"tls: unknown certificate",
+}
+
+// IsCertificateRotationError reports whether err is a TLS handshake failure that may be caused by an in-progress
+// certificate rotation (certificate signed by a CA not yet trusted, expired or not yet valid certificate, or the
+// equivalent alerts from the remote side). Usable with SetRetryIf.
+func IsCertificateRotationError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		return true
+	}
+
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) {
+		return invalid.Reason == x509.Expired
+	}
+
+	msg := err.Error()
+	for _, alert := range tlsRotationAlerts {
+		if strings.Contains(msg, alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReloadOnCertificateRotation returns a BeforeRetry that invokes reload (e.g. reload the root pool or the keypair from
+// disk) when the previous attempt failed with a certificate rotation error
+func ReloadOnCertificateRotation(reload func(ctx context.Context) error) BeforeRetry {
+	return func(ctx context.Context, err error, attempt int) error {
+		if IsCertificateRotationError(err) {
+			return reload(ctx)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_IsCertificateRotationError(t *testing.T) {
+
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("dial: %w", x509.UnknownAuthorityError{}), true},
+		{x509.CertificateInvalidError{Reason: x509.Expired}, true},
+		{x509.CertificateInvalidError{Reason: x509.NotAuthorizedToSign}, false},
+		{errors.New("remote error: tls: bad certificate"), true},
+		{customErr, false},
+		{nil, false},
+	}
+
+	for _, c := range cases {
+		if got := IsCertificateRotationError(c.err); got != c.want {
+			t.Fatalf("IsCertificateRotationError(%v) not equal, want: %v, got %v", c.err, c.want, got)
+		}
+	}
+}
+
+func Test_ReloadOnCertificateRotation(t *testing.T) {
+
+	countReload := 0
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+	retries.SetRetryIf(IsCertificateRotationError)
+	retries.SetBeforeRetry(ReloadOnCertificateRotation(func(ctx context.Context) error {
+		countReload++
+		return nil
+	}))
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if countReload == 0 {
+			return x509.UnknownAuthorityError{}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected: %v", err)
+	}
+
+	if countReload != 1 {
+		t.Fatalf("Count reload not equal, want: %d, got %d", 1, countReload)
+	}
+}
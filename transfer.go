@@ -0,0 +1,37 @@
+package retry
+
+import "context"
+
+// Transfer A resumable transfer for SFTP/FTP-style protocols, retried from the last confirmed offset instead of
+// restarting from the beginning.
+type Transfer struct {
+	// Size total size of the transfer, in bytes
+	Size int64
+	// Stat returns the size confirmed by the remote side (e.g. the size of the remote file for uploads)
+	Stat func(ctx context.Context) (int64, error)
+	// Copy transfers the data starting at offset until the end
+	Copy func(ctx context.Context, offset int64) error
+	// OnResume optional, invoked when an attempt resumes an interrupted transfer
+	OnResume func(ctx context.Context, offset int64, attempt int)
+}
+
+// ExecuteTransfer runs the transfer with retries. Before each attempt, the confirmed offset is read with Stat and the
+// copy resumes from it; the transfer is complete once the confirmed offset reaches Size.
+func (r *Retry) ExecuteTransfer(ctx context.Context, transfer *Transfer) error {
+	return r.Execute(ctx, func(ctx context.Context, attempt int) error {
+		offset := int64(0)
+		if attempt > 1 {
+			var err error
+			if offset, err = transfer.Stat(ctx); err != nil {
+				return err
+			}
+			if offset >= transfer.Size {
+				return nil
+			}
+			if transfer.OnResume != nil {
+				transfer.OnResume(ctx, offset, attempt)
+			}
+		}
+		return transfer.Copy(ctx, offset)
+	})
+}
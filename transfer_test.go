@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_ExecuteTransfer(t *testing.T) {
+
+	remote := int64(0)
+	var offsets []int64
+	var resumed []int64
+
+	retries := New(3, nil)
+	retries.SetFixedBackOff(1)
+
+	err := retries.ExecuteTransfer(context.Background(), &Transfer{
+		Size: 1000,
+		Stat: func(ctx context.Context) (int64, error) {
+			return remote, nil
+		},
+		Copy: func(ctx context.Context, offset int64) error {
+			offsets = append(offsets, offset)
+			// connection drops after 400 bytes
+			remote = offset + 400
+			if remote < 1000 {
+				return customErr
+			}
+			remote = 1000
+			return nil
+		},
+		OnResume: func(ctx context.Context, offset int64, attempt int) {
+			resumed = append(resumed, offset)
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Error not expected")
+	}
+
+	if len(offsets) != 3 || offsets[0] != 0 || offsets[1] != 400 || offsets[2] != 800 {
+		t.Fatalf("Offsets not equal, want: %v, got %v", []int64{0, 400, 800}, offsets)
+	}
+
+	if len(resumed) != 2 {
+		t.Fatalf("Count resumed not equal, want: %d, got %d", 2, len(resumed))
+	}
+}
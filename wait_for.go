@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ReadinessCheck checks whether a dependency is ready (DB ping, migrations applied, cache reachable)
+type ReadinessCheck func(ctx context.Context) error
+
+// WaitFor Keep running the readiness checks until all of them pass or the context is done, replacing the ad-hoc wait
+// loops in main(). Checks are retried forever with an exponential backoff from 100ms to 5s, with full jitter; use
+// a deadline on the context to bound the wait, or Retry.WaitFor for a custom policy.
+func WaitFor(ctx context.Context, checks ...ReadinessCheck) error {
+	r := NewWithOptions(
+		WithRetries(-1),
+		WithExponentialBackoff(100*time.Millisecond, 5*time.Second, 2),
+		WithBackoffJitter(FullJitter, nil),
+	)
+	return r.WaitFor(ctx, checks...)
+}
+
+// WaitFor same as the WaitFor function, following the strategy of this Retry. A check that passed is not run again.
+// When giving up, the error of the last attempt joins the errors of the checks still failing.
+func (r *Retry) WaitFor(ctx context.Context, checks ...ReadinessCheck) error {
+	pending := checks
+	return r.Execute(ctx, func(ctx context.Context, attempt int) error {
+		var failing []ReadinessCheck
+		var errs []error
+		for _, check := range pending {
+			if err := check(ctx); err != nil {
+				failing = append(failing, check)
+				errs = append(errs, err)
+			}
+		}
+		pending = failing
+		return errors.Join(errs...)
+	})
+}
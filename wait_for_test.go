@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_WaitFor(t *testing.T) {
+
+	dbCalls, cacheCalls := 0, 0
+	db := func(ctx context.Context) error {
+		dbCalls++
+		if dbCalls < 3 {
+			return errors.New("db not ready")
+		}
+		return nil
+	}
+	cache := func(ctx context.Context) error {
+		cacheCalls++
+		return nil
+	}
+
+	retries := NewWithOptions(WithRetries(-1), WithFixedBackOff(time.Millisecond))
+	if err := retries.WaitFor(context.Background(), db, cache); err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if dbCalls != 3 {
+		t.Fatalf("Calls not equal, want: %d, got %d", 3, dbCalls)
+	}
+	if cacheCalls != 1 {
+		t.Fatalf("Calls not equal, want: %d, got %d", 1, cacheCalls)
+	}
+}
+
+func Test_WaitForDeadline(t *testing.T) {
+
+	errNotReady := errors.New("not ready")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := WaitFor(ctx, func(ctx context.Context) error {
+		return errNotReady
+	})
+	if !errors.Is(err, errNotReady) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Waiter abstracts the waiting mechanism used for backoff delays, so embedders with their own event loops (game
+// servers, simulators, Wasm) can integrate backoff waits with their scheduler.
+type Waiter interface {
+	// Wait blocks until the duration elapses or the context is done, returning ctx.Err() in the latter case
+	Wait(ctx context.Context, d time.Duration) error
+}
+
+// WaiterFunc An adapter to allow the use of ordinary functions as Waiter
+type WaiterFunc func(ctx context.Context, d time.Duration) error
+
+func (f WaiterFunc) Wait(ctx context.Context, d time.Duration) error {
+	return f(ctx, d)
+}
+
+// TimerWaiter The default Waiter, waits on a runtime timer
+type TimerWaiter struct{}
+
+func (TimerWaiter) Wait(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// uncancelable hides the cancellation of the parent context, keeping its values
+type uncancelable struct {
+	context.Context
+}
+
+func (uncancelable) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (uncancelable) Done() <-chan struct{}       { return nil }
+func (uncancelable) Err() error                  { return nil }
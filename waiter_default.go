@@ -0,0 +1,7 @@
+//go:build !(js && wasm)
+
+package retry
+
+func defaultWaiter() Waiter {
+	return TimerWaiter{}
+}
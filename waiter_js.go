@@ -0,0 +1,35 @@
+//go:build js && wasm
+
+package retry
+
+import (
+	"context"
+	"syscall/js"
+	"time"
+)
+
+// JSWaiter A Waiter backed by the JavaScript setTimeout, the default under GOOS=js GOARCH=wasm. The wait yields to the
+// host event loop instead of relying on the runtime timers.
+type JSWaiter struct{}
+
+func (JSWaiter) Wait(ctx context.Context, d time.Duration) error {
+	fired := make(chan struct{})
+	cb := js.FuncOf(func(this js.Value, args []js.Value) any {
+		close(fired)
+		return nil
+	})
+	defer cb.Release()
+
+	id := js.Global().Call("setTimeout", cb, d.Milliseconds())
+	select {
+	case <-ctx.Done():
+		js.Global().Call("clearTimeout", id)
+		return ctx.Err()
+	case <-fired:
+		return nil
+	}
+}
+
+func defaultWaiter() Waiter {
+	return JSWaiter{}
+}
@@ -0,0 +1,13 @@
+//go:build js && wasm
+
+package retry
+
+import "testing"
+
+var _ Waiter = JSWaiter{}
+
+func Test_DefaultWaiterJS(t *testing.T) {
+	if _, ok := defaultWaiter().(JSWaiter); !ok {
+		t.Fatalf("JSWaiter expected as default waiter, got %T", defaultWaiter())
+	}
+}
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+type warningsKey struct{}
+
+// Warnings The non-fatal warnings reported with Warn during executions. Safe for concurrent use.
+type Warnings struct {
+	mu   sync.Mutex
+	list []error
+}
+
+// Warn reports a non-fatal warning (e.g. a degraded mode was used) from inside a retried callback. Warnings are
+// counted in Stats, exposed by Error.Warnings when the execution gives up, and by the collector of CollectWarnings
+// even when it succeeds. Outside an Execute the warning is dropped and false is returned.
+func Warn(ctx context.Context, warning error) bool {
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok {
+		return false
+	}
+	w.mu.Lock()
+	w.list = append(w.list, warning)
+	w.mu.Unlock()
+	return true
+}
+
+// CollectWarnings returns a context that collects the warnings reported by the executions started with it, including
+// nested ones
+//
+//	ctx, warnings := retry.CollectWarnings(ctx)
+//	err := retries.Execute(ctx, callback)
+//	for _, w := range warnings.List() { ... }
+func CollectWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// List returns the warnings reported so far, in order
+func (w *Warnings) List() []error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]error(nil), w.list...)
+}
+
+// since returns the warnings reported after the first n
+func (w *Warnings) since(n int) []error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.list) <= n {
+		return nil
+	}
+	return append([]error(nil), w.list[n:]...)
+}
+
+func (w *Warnings) len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.list)
+}
+
+// withWarnings returns the collector of the context, installing a new one if there is none
+func withWarnings(ctx context.Context) (context.Context, *Warnings) {
+	if w, ok := ctx.Value(warningsKey{}).(*Warnings); ok {
+		return ctx, w
+	}
+	return CollectWarnings(ctx)
+}
@@ -0,0 +1,57 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Warnings(t *testing.T) {
+
+	errDegraded := errors.New("degraded mode")
+	retries := NewWithOptions(WithRetries(2), WithFixedBackOff(time.Millisecond))
+
+	ctx, warnings := CollectWarnings(context.Background())
+	err := retries.Execute(ctx, func(ctx context.Context, attempt int) error {
+		if !Warn(ctx, errDegraded) {
+			t.Fatalf("Warning not registered")
+		}
+		if attempt < 2 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if list := warnings.List(); len(list) != 2 || list[0] != errDegraded {
+		t.Fatalf("Warnings not expected, got %v", list)
+	}
+	if stats := retries.Stats(); stats.Warnings != 2 {
+		t.Fatalf("Warnings not equal, want: %d, got %d", 2, stats.Warnings)
+	}
+
+	// warnings of previous executions are not reported again
+	err = retries.Execute(ctx, func(ctx context.Context, attempt int) error {
+		Warn(ctx, errDegraded)
+		return Unrecoverable(errors.New("fail"))
+	})
+	var retryErr *Error
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if len(retryErr.Warnings()) != 1 {
+		t.Fatalf("Warnings not equal, want: %d, got %d", 1, len(retryErr.Warnings()))
+	}
+	if len(warnings.List()) != 3 {
+		t.Fatalf("Warnings not equal, want: %d, got %d", 3, len(warnings.List()))
+	}
+	if stats := retries.Stats(); stats.Warnings != 3 {
+		t.Fatalf("Warnings not equal, want: %d, got %d", 3, stats.Warnings)
+	}
+
+	if Warn(context.Background(), errDegraded) {
+		t.Fatalf("Warning registered outside an execution")
+	}
+}
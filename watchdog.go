@@ -0,0 +1,40 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrStuck = errors.New("retry: attempt stuck")
+
+// OnStuck is invoked by the watchdog, from its own goroutine, when an attempt has been running for longer than
+// expected, likely a hang rather than an error. Allows dumping diagnostics, or calling cancel to force-cancel the
+// context of the attempt with ErrStuck as cause (see context.Cause), so the attempt can fail and be retried.
+type OnStuck func(ctx context.Context, attempt int, running time.Duration, cancel func())
+
+// WithWatchdog flags the attempts still running after the given duration, invoking the OnStuck hook once per attempt.
+// Unlike WithAttemptTimeout, attempts are not canceled unless the hook decides so.
+func WithWatchdog(after time.Duration, onStuck OnStuck) Option {
+	return func(r *Retry) {
+		r.stuckAfter = after
+		r.onStuck = onStuck
+	}
+}
+
+// watch starts the watchdog of an attempt, returning the context of the attempt and the function that stops watching
+func (r *Retry) watch(ctx context.Context, attempt int) (context.Context, func()) {
+	if r.stuckAfter <= 0 || r.onStuck == nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(r.stuckAfter, func() {
+		r.onStuck(ctx, attempt, r.stuckAfter, func() {
+			cancel(ErrStuck)
+		})
+	})
+	return ctx, func() {
+		timer.Stop()
+		cancelAfterHolds(ctx, func() { cancel(nil) })
+	}
+}
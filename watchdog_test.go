@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Watchdog(t *testing.T) {
+
+	var stuck int32
+	retries := NewWithOptions(
+		WithRetries(1),
+		WithFixedBackOff(time.Millisecond),
+		WithWatchdog(10*time.Millisecond, func(ctx context.Context, attempt int, running time.Duration, cancel func()) {
+			atomic.AddInt32(&stuck, 1)
+			if attempt != 1 || running != 10*time.Millisecond {
+				t.Errorf("Stuck attempt not expected, attempt: %d, running: %v", attempt, running)
+			}
+			cancel()
+		}),
+	)
+
+	err := retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		if attempt == 1 {
+			// hangs until force-canceled
+			<-ctx.Done()
+			if !errors.Is(context.Cause(ctx), ErrStuck) {
+				t.Errorf("Cause not equal, want: %v, got %v", ErrStuck, context.Cause(ctx))
+			}
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error not expected, got %v", err)
+	}
+	if atomic.LoadInt32(&stuck) != 1 {
+		t.Fatalf("Stuck not equal, want: %d, got %d", 1, stuck)
+	}
+}
+
+func Test_WatchdogFastAttempt(t *testing.T) {
+
+	var stuck int32
+	retries := NewWithOptions(
+		WithWatchdog(10*time.Millisecond, func(ctx context.Context, attempt int, running time.Duration, cancel func()) {
+			atomic.AddInt32(&stuck, 1)
+		}),
+	)
+
+	_ = retries.Execute(context.Background(), func(ctx context.Context, attempt int) error {
+		return nil
+	})
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&stuck) != 0 {
+		t.Fatalf("Stuck not equal, want: %d, got %d", 0, stuck)
+	}
+}